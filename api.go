@@ -24,6 +24,7 @@ var (
 	ErrorInvalidRequestID        = fmt.Errorf("invalid request ID")
 	ErrorInvalidClientID         = fmt.Errorf("invalid client ID")
 	ErrorInvalidTagID            = fmt.Errorf("invalid tag ID")
+	ErrorInvalidTagGroupID       = fmt.Errorf("invalid tag group ID")
 	ErrorInvalidChannelID        = fmt.Errorf("invalid channel ID")
 	ErrorInvalidOperatorGroupID  = fmt.Errorf("invalid operator group ID")
 	ErrorInvalidOperatorID       = fmt.Errorf("invalid operator ID")
@@ -51,10 +52,15 @@ type BasicResponse struct {
 }
 type Ctd struct {
 	logging.CustomLogger
-	Url       string
-	Token     string
-	Timeout   uint
-	lastError any // Last error encountered during API requests
+	Url            string
+	Token          string
+	Timeout        uint
+	Redactor       *Redactor           // Redactor: Optional redactor applied to the log output
+	Limiter        *RateLimiter        // Limiter: Optional rate limiter applied to all API requests
+	Verifier       *WebhookVerifier    // Verifier: Optional webhook verifier; its secret is added to the URLs of created and updated webhooks
+	Capabilities   *CapabilityRegistry // Capabilities: Transport capabilities of the company, sent messages are validated against them (nil - DefaultCapabilities)
+	SkipValidation bool                // SkipValidation: Send messages without checking them against the transport capabilities
+	lastError      any                 // Last error encountered during API requests
 }
 
 // Init initializes the Ctd instance with the provided URL and token.
@@ -192,6 +198,7 @@ func (dst *Ctd) Delete(ctx context.Context, path string, response any) ([]byte,
 // It handles the request creation, sending, and response reading.
 // The method supports GET, POST, PUT, and DELETE requests.
 // It sets the appropriate headers, including the Authorization header if a token is provided.
+// If a rate limiter is configured, it waits for the limiter before sending the request.
 // It also measures the time taken for the request and logs debug information.
 // If the response body contains an error message indicating an invalid token,
 // it returns an ErrorInvalidToken error.
@@ -206,6 +213,10 @@ func (dst *Ctd) Delete(ctx context.Context, path string, response any) ([]byte,
 //   - A byte slice containing the response data from the API.
 //   - An error if the request fails, if the response is invalid, or if the response indicates an invalid token.
 func (dst *Ctd) doRequest(ctx context.Context, method string, url string, payload any, response any) ([]byte, error) {
+	if err := dst.Limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
 	start := time.Now()
 	client := &http.Client{
 		Timeout: time.Duration(dst.Timeout) * time.Second,
@@ -228,7 +239,7 @@ func (dst *Ctd) doRequest(ctx context.Context, method string, url string, payloa
 		req, err = http.NewRequest(method, url, bytes.NewBuffer(data))
 	}
 	if err != nil {
-		dst.Error(ctx, fmt.Sprintf("%v", err))
+		dst.Error(ctx, "Failed to create request: %s", dst.redact(err.Error()))
 		return nil, err
 	}
 
@@ -238,7 +249,7 @@ func (dst *Ctd) doRequest(ctx context.Context, method string, url string, payloa
 	req.Header.Set("Content-Type", "application/json")
 
 	res, err := client.Do(req)
-	dst.Debug(ctx, fmt.Sprintf("\033[1m\033[36mAPI %s (%.2f ms)\033[1m \033[35m%s\033[0m", method, float64(time.Since(start))/1000000, url))
+	dst.Debug(ctx, fmt.Sprintf("\033[1m\033[36mAPI %s (%.2f ms)\033[1m \033[35m%s\033[0m", method, float64(time.Since(start))/1000000, dst.redact(url)))
 	if err != nil {
 		return nil, err
 	}
@@ -259,7 +270,7 @@ func (dst *Ctd) doRequest(ctx context.Context, method string, url string, payloa
 	if response != nil {
 		err = json.Unmarshal(body, response)
 		if err != nil {
-			dst.Errorf(ctx, "Failed to unmarshal response (%s): %v", body, err)
+			dst.Errorf(ctx, "Failed to unmarshal response (%s): %v", dst.redact(string(body)), err)
 			return body, ErrorInvalidResponse
 		}
 	}
@@ -276,3 +287,9 @@ func (dst *Ctd) doRequest(ctx context.Context, method string, url string, payloa
 func (dst *Ctd) LastError() any {
 	return dst.lastError
 }
+
+// redact removes personal data from the string using the configured Redactor.
+// If no Redactor is configured, the string is returned unchanged.
+func (dst *Ctd) redact(str string) string {
+	return dst.Redactor.Redact(str)
+}
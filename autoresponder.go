@@ -0,0 +1,340 @@
+package ctd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	HoursAny    = ""       // Rule applies at any time
+	HoursOpen   = "open"   // Rule applies within the working hours
+	HoursClosed = "closed" // Rule applies outside the working hours
+)
+
+// AutoresponderRule is a rule of the Autoresponder. All the set conditions must match
+// for the actions to be executed. Actions are executed in the order: reply, tags, transfer, close.
+type AutoresponderRule struct {
+	Name string // Name: Unique name of the rule, used for rate limiting
+
+	// Conditions
+	Channels         []int64           // Channels: Channel IDs (empty - any channel)
+	Transports       []string          // Transports: Transports (empty - any transport)
+	Calendar         *BusinessCalendar // Calendar: Working hours checked by Hours
+	Hours            string            // Hours: When the rule applies according to the calendar ('', 'open' or 'closed')
+	Keywords         []string          // Keywords: Words, one of which the text must contain, case-insensitively (empty - any text)
+	Pattern          *regexp.Regexp    // Pattern: Regular expression the text must match (nil - any text)
+	ClientTags       []int64           // ClientTags: Tag IDs, one of which the client must have (empty - any client)
+	NoOperatorOnline bool              // NoOperatorOnline: Rule applies only if no operator is online
+	OperatorGroupID  int64             // OperatorGroupID: Group checked by NoOperatorOnline (0 - all operators)
+
+	// Actions
+	Reply           *MessagePayload // Reply: Message sent to the client as 'autoreply'
+	TagIDs          []int64         // TagIDs: Tags assigned to the request with AddTagToRequest
+	TransferGroupID int64           // TransferGroupID: Group the message is transferred to
+	CloseDialog     bool            // CloseDialog: Close the dialog
+	CloseOperatorID int64           // CloseOperatorID: Operator closing the dialog (required with CloseDialog)
+
+	Cooldown time.Duration // Cooldown: Minimum interval between the executions for the same client (0 - Autoresponder.Cooldown)
+	Stop     bool          // Stop: Don't check the next rules if this one matches
+}
+
+// Autoresponder executes the actions of the rules matching the inbound client messages.
+// To avoid spamming the clients, a rule is executed for the same client at most once per cooldown.
+// Executions failed before the reply was sent don't count, so the rule is tried again on the next message.
+// Handle matches the callback of WebhookDispatcher.
+type Autoresponder struct {
+	Rules    []*AutoresponderRule // Rules: Rules checked in order
+	Cooldown time.Duration        // Cooldown: Default minimum interval between the executions of a rule for the same client (default: 1 hour)
+
+	ctd  *Ctd
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// autoresponderEvent caches the data loaded for the conditions of an event.
+type autoresponderEvent struct {
+	notification *WebhookNotification
+	now          time.Time
+	client       *Client
+	operators    []Operator
+	groups       map[int64]*OperatorGroup
+}
+
+// NewAutoresponder creates a new Autoresponder with the rules.
+//
+// Parameters:
+//   - rules: The rules checked in order.
+//
+// Returns:
+//   - A pointer to an Autoresponder.
+func (dst *Ctd) NewAutoresponder(rules ...*AutoresponderRule) *Autoresponder {
+	return &Autoresponder{
+		Rules: rules,
+		ctd:   dst,
+		last:  map[string]time.Time{},
+	}
+}
+
+// Match returns the rules matching the notification, ignoring the cooldown.
+// Notifications other than inbound client messages don't match any rule.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - notification: The webhook notification.
+//   - now: The time the working hours are checked at.
+//
+// Returns:
+//   - A slice of the matching rules.
+//   - An error if loading the client or the operators fails.
+func (dst *Autoresponder) Match(ctx context.Context, notification *WebhookNotification, now time.Time) ([]*AutoresponderRule, error) {
+	if notification.HookType != "inbox" || (notification.Type != "" && notification.Type != "from_client") {
+		return nil, nil
+	}
+
+	event := &autoresponderEvent{notification: notification, now: now, groups: map[int64]*OperatorGroup{}}
+	result := []*AutoresponderRule{}
+	for _, rule := range dst.Rules {
+		ok, err := dst.matches(ctx, rule, event)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		result = append(result, rule)
+		if rule.Stop {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// Handle executes the actions of the rules matching the notification.
+// Rules executed for the client within their cooldown are skipped.
+// An error of a rule doesn't stop the other rules. The cooldown of a failed rule starts only if its reply was sent.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - notification: The webhook notification.
+//
+// Returns:
+//   - The errors of the conditions and the actions, joined.
+func (dst *Autoresponder) Handle(ctx context.Context, notification *WebhookNotification) error {
+	now := time.Now()
+	rules, err := dst.Match(ctx, notification, now)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, rule := range rules {
+		previous, ok := dst.allow(rule, notification.ClientID, now)
+		if !ok {
+			continue
+		}
+		if replied, err := dst.execute(ctx, rule, notification); err != nil {
+			if !replied {
+				// Nothing reached the client, so the rule may run again on the next message
+				dst.release(rule, notification.ClientID, now, previous)
+			}
+			dst.ctd.Error(ctx, "Autoresponder rule %q failed: %v", rule.Name, err)
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// matches checks the conditions of the rule, cheapest first.
+func (dst *Autoresponder) matches(ctx context.Context, rule *AutoresponderRule, event *autoresponderEvent) (bool, error) {
+	notification := event.notification
+
+	if len(rule.Channels) > 0 && !slices.Contains(rule.Channels, notification.ChannelID) {
+		return false, nil
+	}
+	if len(rule.Transports) > 0 && !slices.Contains(rule.Transports, notification.Transport) {
+		return false, nil
+	}
+	if rule.Calendar != nil && rule.Hours != HoursAny {
+		if rule.Calendar.IsOpen(event.now) != (rule.Hours == HoursOpen) {
+			return false, nil
+		}
+	}
+	if len(rule.Keywords) > 0 {
+		text := strings.ToLower(notification.Text)
+		if !slices.ContainsFunc(rule.Keywords, func(keyword string) bool {
+			return strings.Contains(text, strings.ToLower(keyword))
+		}) {
+			return false, nil
+		}
+	}
+	if rule.Pattern != nil && !rule.Pattern.MatchString(notification.Text) {
+		return false, nil
+	}
+
+	if len(rule.ClientTags) > 0 {
+		if event.client == nil {
+			client, err := dst.ctd.GetClient(ctx, int(notification.ClientID))
+			if err != nil {
+				return false, err
+			}
+			event.client = client
+		}
+		if !slices.ContainsFunc(event.client.Tags, func(tag Tag) bool {
+			return slices.Contains(rule.ClientTags, int64(tag.ID))
+		}) {
+			return false, nil
+		}
+	}
+
+	if rule.NoOperatorOnline {
+		online, err := dst.operatorOnline(ctx, rule.OperatorGroupID, event)
+		if err != nil {
+			return false, err
+		}
+		if online {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// operatorOnline reports whether an operator of the group (or any operator) is online.
+func (dst *Autoresponder) operatorOnline(ctx context.Context, group_id int64, event *autoresponderEvent) (bool, error) {
+	if event.operators == nil {
+		operators, err := dst.ctd.AllOperators(ctx)
+		if err != nil {
+			return false, err
+		}
+		event.operators = operators
+	}
+
+	var members []int64
+	if group_id != 0 {
+		group, ok := event.groups[group_id]
+		if !ok {
+			var err error
+			if group, err = dst.ctd.GetOperatorGroup(ctx, group_id); err != nil {
+				return false, err
+			}
+			event.groups[group_id] = group
+		}
+		members = group.Operators
+	}
+
+	for _, operator := range event.operators {
+		if operator.Online == 1 && (group_id == 0 || slices.Contains(members, operator.ID)) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// allow checks the cooldown of the rule for the client and reserves it for the execution,
+// so concurrent messages of the client don't execute the rule twice.
+// It returns the time of the previous execution for release.
+func (dst *Autoresponder) allow(rule *AutoresponderRule, client_id int64, now time.Time) (time.Time, bool) {
+	cooldown := rule.Cooldown
+	if cooldown <= 0 {
+		cooldown = dst.Cooldown
+	}
+	if cooldown <= 0 {
+		cooldown = time.Hour
+	}
+
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	key := autoresponderKey(rule, client_id)
+	last, ok := dst.last[key]
+	if ok && now.Sub(last) < cooldown {
+		return time.Time{}, false
+	}
+	dst.last[key] = now
+
+	// Forget the clients whose cooldown is over to keep the map small
+	if len(dst.last) > 10000 {
+		for item, last := range dst.last {
+			if now.Sub(last) > 24*time.Hour {
+				delete(dst.last, item)
+			}
+		}
+	}
+
+	return last, true
+}
+
+// release restores the cooldown of the rule for the client after a failed execution.
+func (dst *Autoresponder) release(rule *AutoresponderRule, client_id int64, now, previous time.Time) {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	key := autoresponderKey(rule, client_id)
+	if last, ok := dst.last[key]; !ok || !last.Equal(now) {
+		return
+	}
+	if previous.IsZero() {
+		delete(dst.last, key)
+		return
+	}
+	dst.last[key] = previous
+}
+
+// autoresponderKey returns the cooldown key of the rule for the client.
+func autoresponderKey(rule *AutoresponderRule, client_id int64) string {
+	return rule.Name + ":" + strconv.FormatInt(client_id, 10)
+}
+
+// execute runs the actions of the rule and reports whether the reply was sent.
+func (dst *Autoresponder) execute(ctx context.Context, rule *AutoresponderRule, notification *WebhookNotification) (bool, error) {
+	if rule.CloseDialog && rule.CloseOperatorID == 0 {
+		// Inbound messages have no operator to close the dialog on behalf of
+		return false, fmt.Errorf("%w: rule %s closes the dialog without CloseOperatorID", ErrorInvalidParameters, rule.Name)
+	}
+
+	if rule.Reply != nil {
+		reply := *rule.Reply
+		reply.Type = "autoreply"
+		reply.ClientID = notification.ClientID
+		if reply.ChannelID == 0 {
+			reply.ChannelID = notification.ChannelID
+		}
+		if reply.Transport == "" {
+			reply.Transport = notification.Transport
+		}
+		if _, err := dst.ctd.SendMessage(ctx, &reply); err != nil {
+			return false, err
+		}
+	}
+	replied := rule.Reply != nil
+
+	if len(rule.TagIDs) > 0 && notification.RequestID != 0 {
+		if err := dst.ctd.AddTagToRequest(ctx, rule.TagIDs, notification.RequestID); err != nil {
+			return replied, err
+		}
+	}
+
+	if rule.TransferGroupID != 0 {
+		if err := dst.ctd.TransferToGroup(ctx, notification.MessageID, rule.TransferGroupID, false); err != nil {
+			return replied, err
+		}
+	}
+
+	if rule.CloseDialog && notification.DialogID != 0 {
+		if err := dst.ctd.CloseDialog(ctx, notification.DialogID, rule.CloseOperatorID, 0); err != nil {
+			return replied, err
+		}
+	}
+
+	return replied, nil
+}
@@ -0,0 +1,131 @@
+package ctd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoresponder(t *testing.T) {
+	ctx := context.Background()
+
+	fake := &fakeMessagesServer{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	dst := &Ctd{}
+	dst.Init(server.URL, "token")
+
+	calendar, err := NewBusinessCalendar(time.UTC, "09:00", "18:00", time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+	require.NoError(t, err, "NewBusinessCalendar() error")
+
+	night := &AutoresponderRule{
+		Name:            "night",
+		Calendar:        calendar,
+		Hours:           HoursClosed,
+		Reply:           &MessagePayload{Text: "We are closed"},
+		TransferGroupID: 9,
+	}
+	price := &AutoresponderRule{
+		Name:       "price",
+		Transports: []string{"telegram"},
+		Keywords:   []string{"price", "cost"},
+		Reply:      &MessagePayload{Text: "See our prices"},
+		Stop:       true,
+	}
+	order := &AutoresponderRule{
+		Name:    "order",
+		Pattern: regexp.MustCompile(`#\d+`),
+		Reply:   &MessagePayload{Text: "Checking your order"},
+	}
+	autoresponder := dst.NewAutoresponder(night, price, order)
+
+	monday := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	sunday := time.Date(2026, 1, 4, 12, 0, 0, 0, time.UTC)
+	message := func(transport, text string) *WebhookNotification {
+		return &WebhookNotification{HookType: "inbox", Type: "from_client", MessageID: 11, ClientID: 1, ChannelID: 2, Transport: transport, Text: text}
+	}
+
+	tests := []struct {
+		name         string
+		notification *WebhookNotification
+		now          time.Time
+		want         []*AutoresponderRule
+	}{
+		{"Working hours", message("telegram", "hello"), monday, []*AutoresponderRule{}},
+		{"Outside working hours", message("telegram", "hello"), sunday, []*AutoresponderRule{night}},
+		{"Keyword", message("telegram", "What is the PRICE of #12?"), monday, []*AutoresponderRule{price}},
+		{"Keyword of other transport", message("whatsapp", "What is the price of #12?"), monday, []*AutoresponderRule{order}},
+		{"Outbound message", &WebhookNotification{HookType: "outbox", Text: "price"}, sunday, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := autoresponder.Match(ctx, tt.notification, tt.now)
+			require.NoError(t, err, "autoresponder.Match() error")
+			require.Equal(t, tt.want, got, "autoresponder.Match()")
+		})
+	}
+
+	t.Run("Rate limit", func(t *testing.T) {
+		autoresponder := dst.NewAutoresponder(order)
+		require.NoError(t, autoresponder.Handle(ctx, message("whatsapp", "order #1")), "autoresponder.Handle() error")
+		require.NoError(t, autoresponder.Handle(ctx, message("whatsapp", "order #1?")), "autoresponder.Handle() error")
+		require.Len(t, fake.messages, 1, "the client should get the reply once per cooldown")
+		require.Equal(t, "autoreply", fake.messages[0].Type, "reply should be sent as autoreply")
+		require.Equal(t, int64(1), fake.messages[0].ClientID, "reply should be sent to the client")
+
+		other := message("whatsapp", "order #2")
+		other.ClientID = 2
+		require.NoError(t, autoresponder.Handle(ctx, other), "autoresponder.Handle() error")
+		require.Len(t, fake.messages, 2, "other clients should get the reply")
+	})
+
+	t.Run("Failed action after the reply", func(t *testing.T) {
+		tag := &AutoresponderRule{Name: "tag", Keywords: []string{"refund"}, Reply: &MessagePayload{Text: "Refunds take 3 days"}, TagIDs: []int64{4}}
+		autoresponder := dst.NewAutoresponder(tag)
+		notification := message("whatsapp", "refund")
+		notification.ClientID = 3
+		notification.RequestID = 5
+
+		sent := len(fake.texts())
+		require.Error(t, autoresponder.Handle(ctx, notification), "autoresponder.Handle() should return the failed action")
+		require.NoError(t, autoresponder.Handle(ctx, notification), "the rule should be within the cooldown")
+		require.Len(t, fake.texts(), sent+1, "the reply should not be sent again")
+	})
+
+	t.Run("Failed rules without a reply are retried", func(t *testing.T) {
+		attempts := atomic.Int32{}
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer failing.Close()
+
+		api := &Ctd{}
+		api.Init(failing.URL, "token")
+		autoresponder := api.NewAutoresponder(&AutoresponderRule{Name: "tag", TagIDs: []int64{4}})
+		notification := message("whatsapp", "refund")
+		notification.RequestID = 5
+
+		require.Error(t, autoresponder.Handle(ctx, notification), "autoresponder.Handle() should return the failed action")
+		require.Error(t, autoresponder.Handle(ctx, notification), "autoresponder.Handle() should return the failed action")
+		require.Equal(t, int32(2), attempts.Load(), "the failed rule should not start the cooldown")
+	})
+
+	t.Run("Close without operator", func(t *testing.T) {
+		close := &AutoresponderRule{Name: "close", Keywords: []string{"bye"}, Reply: &MessagePayload{Text: "Bye"}, CloseDialog: true}
+		autoresponder := dst.NewAutoresponder(close)
+		notification := message("whatsapp", "bye")
+		notification.DialogID = 8
+
+		sent := len(fake.texts())
+		require.ErrorIs(t, autoresponder.Handle(ctx, notification), ErrorInvalidParameters, "CloseOperatorID should be required")
+		require.Len(t, fake.texts(), sent, "no actions should be executed")
+	})
+}
@@ -0,0 +1,138 @@
+package ctd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// BulkTagOptions controls the execution of the bulk tag operations.
+type BulkTagOptions struct {
+	Workers    int           // Workers: Number of concurrent workers (default: 4)
+	Retries    int           // Retries: Number of retries of transient errors (default: 0)
+	RetryDelay time.Duration // RetryDelay: Delay before the first retry, doubled on every next one (default: 1 second)
+}
+
+// BulkTagResult describes the result of a bulk tag operation for a single assignee.
+type BulkTagResult struct {
+	AssigneeID int64 // AssigneeID: ID of the client or request
+	Attempts   int   // Attempts: Number of attempts made
+	Error      error // Error: Final error, nil on success
+}
+
+// IsInvalidAssignee reports whether the operation failed because the client or request doesn't exist.
+func (dst *BulkTagResult) IsInvalidAssignee() bool {
+	return errors.Is(dst.Error, ErrorInvalidClientID) || errors.Is(dst.Error, ErrorInvalidRequestID)
+}
+
+// IsTransient reports whether the operation failed because of a transport failure
+// that may succeed if repeated later.
+func (dst *BulkTagResult) IsTransient() bool {
+	return isTransientError(dst.Error)
+}
+
+// BulkAssignTags assigns the same tags to many clients or requests.
+// Assignees are processed concurrently by the specified number of workers, every API request
+// goes through the rate limiter of the Ctd instance (if configured), and transient errors are retried.
+// Once the context is canceled, the remaining assignees are skipped with the context error.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - tag_ids: A slice of tag IDs to be assigned.
+//   - mode: The mode of assignment ('client' or 'request').
+//   - ids: The IDs of the clients or requests.
+//   - options: The bulk operation options (may be nil).
+//
+// Returns:
+//   - A slice of BulkTagResult in the order of the IDs.
+func (dst *Ctd) BulkAssignTags(ctx context.Context, tag_ids []int64, mode string, ids []int64, options *BulkTagOptions) []BulkTagResult {
+	opts := BulkTagOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = time.Second
+	}
+
+	results := make([]BulkTagResult, len(ids))
+	jobs := make(chan int)
+
+	wg := sync.WaitGroup{}
+	for range opts.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					results[i] = BulkTagResult{AssigneeID: ids[i], Error: err}
+					continue
+				}
+				results[i] = dst.assignTagsWithRetry(ctx, tag_ids, mode, ids[i], &opts)
+			}
+		}()
+	}
+
+	for i := range ids {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// assignTagsWithRetry assigns tags to a single assignee retrying transient errors.
+func (dst *Ctd) assignTagsWithRetry(ctx context.Context, tag_ids []int64, mode string, id int64, options *BulkTagOptions) BulkTagResult {
+	result := BulkTagResult{AssigneeID: id}
+	delay := options.RetryDelay
+
+	for {
+		result.Attempts++
+
+		response, err := dst.APIAssignTag(ctx, tag_ids, mode, id)
+		if err == nil && response.Status != "success" {
+			err = ErrorInvalidParameters
+		}
+		result.Error = err
+
+		if err == nil || !isTransientError(err) || result.Attempts > options.Retries {
+			return result
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			result.Error = ctx.Err()
+			return result
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// isTransientError reports whether the error is a transport failure rather than
+// an error reported by the Chat2Desk API. Network errors, timeouts and response bodies
+// that aren't JSON, such as the HTML pages of 5xx and 429 replies, are transient.
+func isTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	return errors.Is(err, ErrorInvalidResponse) || errors.Is(err, io.ErrUnexpectedEOF)
+}
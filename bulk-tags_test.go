@@ -0,0 +1,96 @@
+package ctd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ra-company/env"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCtd_BulkAssignTags(t *testing.T) {
+	ctx := context.Background()
+
+	url, token := getCredentials(t)
+
+	tagID := int64(env.GetEnvInt("API_TAG_ID", 0))
+	require.NotEqual(t, int64(0), tagID, "API_TAG_ID must be set in .env file or .settings")
+	clientID := int64(env.GetEnvInt("API_CLIENT_ID", 0))
+	require.NotEqual(t, int64(0), clientID, "API_CLIENT_ID must be set in .env file or .settings")
+
+	dst := &Ctd{Limiter: NewRateLimiter(5)}
+	dst.Init(url, token)
+
+	results := dst.BulkAssignTags(ctx, []int64{tagID}, "client", []int64{clientID, 0}, &BulkTagOptions{Workers: 2, Retries: 1})
+	require.Len(t, results, 2, "dst.BulkAssignTags() should return result for every assignee")
+	require.NoError(t, results[0].Error, "dst.BulkAssignTags() error")
+	require.Equal(t, clientID, results[0].AssigneeID, "dst.BulkAssignTags() should keep the order of assignees")
+	require.True(t, results[1].IsInvalidAssignee(), "dst.BulkAssignTags() should report invalid client")
+	require.Equal(t, 1, results[1].Attempts, "dst.BulkAssignTags() should not retry invalid client")
+}
+
+func TestCtd_BulkAssignTagsCanceled(t *testing.T) {
+	requests := atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"status":"success"}`))
+	}))
+	defer server.Close()
+
+	dst := &Ctd{}
+	dst.Init(server.URL, "token")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := dst.BulkAssignTags(ctx, []int64{1}, "client", []int64{1, 2, 3}, &BulkTagOptions{Workers: 2})
+	require.Len(t, results, 3, "dst.BulkAssignTags() should return result for every assignee")
+	for i, result := range results {
+		require.ErrorIs(t, result.Error, context.Canceled, "dst.BulkAssignTags() should return the context error")
+		require.Equal(t, int64(i+1), result.AssigneeID, "dst.BulkAssignTags() should keep the order of assignees")
+		require.Zero(t, result.Attempts, "canceled assignees should not be attempted")
+	}
+	require.Zero(t, requests.Load(), "no requests should be sent after cancellation")
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	ctx := context.Background()
+
+	limiter := NewRateLimiter(20)
+	start := time.Now()
+	for range 5 {
+		require.NoError(t, limiter.Wait(ctx), "limiter.Wait() error")
+	}
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond, "limiter.Wait() should spread requests")
+
+	var unlimited *RateLimiter
+	require.NoError(t, unlimited.Wait(ctx), "nil limiter should not limit requests")
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	limiter = NewRateLimiter(0.1)
+	require.NoError(t, limiter.Wait(canceled), "the first request should not wait")
+	require.ErrorIs(t, limiter.Wait(canceled), context.Canceled, "limiter.Wait() should return context error")
+}
+
+func TestIsTransientError(t *testing.T) {
+	require.False(t, isTransientError(nil), "nil is not transient")
+	require.False(t, isTransientError(ErrorInvalidClientID), "ErrorInvalidClientID is not transient")
+	require.False(t, isTransientError(ErrorInvalidRequestID), "ErrorInvalidRequestID is not transient")
+	require.False(t, isTransientError(fmt.Errorf("assign: %w", ErrorInvalidClientID)), "wrapped API errors are not transient")
+	require.False(t, isTransientError(ErrorInvalidParameters), "API error replies are not transient")
+	require.False(t, isTransientError(context.Canceled), "canceled context is not transient")
+	require.False(t, isTransientError(errors.New("unknown error")), "unknown errors are not transient")
+	require.True(t, isTransientError(&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}), "network errors are transient")
+	require.True(t, isTransientError(&url.Error{Op: "Post", URL: "https://api.chat2desk.com", Err: context.DeadlineExceeded}), "request timeouts are transient")
+	require.True(t, isTransientError(ErrorInvalidResponse), "responses that aren't JSON are transient")
+}
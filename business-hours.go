@@ -0,0 +1,186 @@
+package ctd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// BusinessCalendar describes the working hours of the support in a time zone, with holidays.
+// Working hours are set per weekday as windows of local time; a day without windows is a day off.
+type BusinessCalendar struct {
+	Location *time.Location // Location: Time zone of the working hours (default: UTC)
+
+	hours    map[time.Weekday][][2]int
+	holidays map[string]bool
+}
+
+// NewBusinessCalendar creates a new BusinessCalendar with the same working hours on the specified days.
+//
+// Parameters:
+//   - location: The time zone of the working hours (nil - UTC).
+//   - start: The start of the working day ('HH:MM').
+//   - end: The end of the working day ('HH:MM', '24:00' for midnight).
+//   - days: The working days.
+//
+// Returns:
+//   - A pointer to a BusinessCalendar.
+//   - ErrorInvalidParameters if the time is invalid.
+func NewBusinessCalendar(location *time.Location, start, end string, days ...time.Weekday) (*BusinessCalendar, error) {
+	calendar := &BusinessCalendar{
+		Location: location,
+		hours:    map[time.Weekday][][2]int{},
+		holidays: map[string]bool{},
+	}
+
+	for _, day := range days {
+		if err := calendar.SetHours(day, start, end); err != nil {
+			return nil, err
+		}
+	}
+
+	return calendar, nil
+}
+
+// SetHours replaces the working hours of the weekday.
+//
+// Parameters:
+//   - day: The weekday.
+//   - windows: Pairs of the start and end times ('HH:MM'); no windows make the day a day off.
+//
+// Returns:
+//   - ErrorInvalidParameters if the time is invalid or the window is empty.
+func (dst *BusinessCalendar) SetHours(day time.Weekday, windows ...string) error {
+	if len(windows)%2 != 0 {
+		return ErrorInvalidParameters
+	}
+
+	result := [][2]int{}
+	for i := 0; i < len(windows); i += 2 {
+		start, err := parseClock(windows[i])
+		if err != nil {
+			return err
+		}
+		end, err := parseClock(windows[i+1])
+		if err != nil {
+			return err
+		}
+		if end <= start {
+			return ErrorInvalidParameters
+		}
+		result = append(result, [2]int{start, end})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i][0] < result[j][0]
+	})
+
+	if dst.hours == nil {
+		dst.hours = map[time.Weekday][][2]int{}
+	}
+	dst.hours[day] = result
+
+	return nil
+}
+
+// AddHolidays marks the dates as days off.
+//
+// Parameters:
+//   - dates: The dates ('YYYY-MM-DD').
+//
+// Returns:
+//   - ErrorInvalidParameters if a date is invalid.
+func (dst *BusinessCalendar) AddHolidays(dates ...string) error {
+	if dst.holidays == nil {
+		dst.holidays = map[string]bool{}
+	}
+
+	for _, date := range dates {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return ErrorInvalidParameters
+		}
+		dst.holidays[date] = true
+	}
+
+	return nil
+}
+
+// IsOpen reports whether the time is within the working hours.
+func (dst *BusinessCalendar) IsOpen(t time.Time) bool {
+	t = t.In(dst.location())
+	minute := t.Hour()*60 + t.Minute()
+
+	for _, window := range dst.windows(t) {
+		if minute >= window[0] && minute < window[1] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WorkingDuration returns the working time between two moments, excluding days off, holidays and breaks.
+// If to is before from, it returns zero.
+//
+// Parameters:
+//   - from: The start of the period.
+//   - to: The end of the period.
+//
+// Returns:
+//   - The working time within the period.
+func (dst *BusinessCalendar) WorkingDuration(from, to time.Time) time.Duration {
+	location := dst.location()
+	from, to = from.In(location), to.In(location)
+	if !to.After(from) {
+		return 0
+	}
+
+	result := time.Duration(0)
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, location)
+	for day.Before(to) {
+		for _, window := range dst.windows(day) {
+			// Wall clock bounds, so the windows keep their hours on the days of DST transitions
+			start := time.Date(day.Year(), day.Month(), day.Day(), 0, window[0], 0, 0, location)
+			end := time.Date(day.Year(), day.Month(), day.Day(), 0, window[1], 0, 0, location)
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if end.After(start) {
+				result += end.Sub(start)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return result
+}
+
+// windows returns the working hours of the day of the time, in minutes since midnight.
+func (dst *BusinessCalendar) windows(t time.Time) [][2]int {
+	if dst.holidays[t.Format("2006-01-02")] {
+		return nil
+	}
+	return dst.hours[t.Weekday()]
+}
+
+// location returns the time zone of the calendar.
+func (dst *BusinessCalendar) location() *time.Location {
+	if dst.Location == nil {
+		return time.UTC
+	}
+	return dst.Location
+}
+
+// parseClock converts the 'HH:MM' time to minutes since midnight.
+func parseClock(str string) (int, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(str, "%d:%d", &hour, &minute); err != nil {
+		return 0, ErrorInvalidParameters
+	}
+	if hour < 0 || minute < 0 || minute > 59 || hour*60+minute > 24*60 {
+		return 0, ErrorInvalidParameters
+	}
+	return hour*60 + minute, nil
+}
@@ -0,0 +1,86 @@
+package ctd
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusinessCalendar_IsOpen(t *testing.T) {
+	location := time.FixedZone("UTC+3", 3*60*60)
+
+	_, err := NewBusinessCalendar(location, "18:00", "09:00", time.Monday)
+	require.ErrorIs(t, err, ErrorInvalidParameters, "empty window should be rejected")
+	_, err = NewBusinessCalendar(location, "9", "18:00", time.Monday)
+	require.ErrorIs(t, err, ErrorInvalidParameters, "invalid time should be rejected")
+
+	calendar, err := NewBusinessCalendar(location, "09:00", "18:00", time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+	require.NoError(t, err, "NewBusinessCalendar() error")
+	require.NoError(t, calendar.SetHours(time.Saturday, "10:00", "12:00", "13:00", "24:00"), "calendar.SetHours() error")
+	require.NoError(t, calendar.AddHolidays("2026-01-01"), "calendar.AddHolidays() error")
+	require.ErrorIs(t, calendar.AddHolidays("01.01.2026"), ErrorInvalidParameters, "invalid date should be rejected")
+
+	tests := []struct {
+		name string
+		time time.Time
+		open bool
+	}{
+		{"Monday morning", time.Date(2026, 1, 5, 9, 0, 0, 0, location), true},
+		{"Monday before opening", time.Date(2026, 1, 5, 8, 59, 0, 0, location), false},
+		{"Monday closing", time.Date(2026, 1, 5, 18, 0, 0, 0, location), false},
+		{"Monday in UTC", time.Date(2026, 1, 5, 14, 30, 0, 0, time.UTC), true},
+		{"Monday evening in UTC", time.Date(2026, 1, 5, 15, 30, 0, 0, time.UTC), false},
+		{"Saturday break", time.Date(2026, 1, 3, 12, 30, 0, 0, location), false},
+		{"Saturday late", time.Date(2026, 1, 3, 23, 59, 0, 0, location), true},
+		{"Sunday", time.Date(2026, 1, 4, 12, 0, 0, 0, location), false},
+		{"Holiday", time.Date(2026, 1, 1, 12, 0, 0, 0, location), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.open, calendar.IsOpen(tt.time), "calendar.IsOpen()")
+		})
+	}
+}
+
+func TestBusinessCalendar_WorkingDuration(t *testing.T) {
+	calendar, err := NewBusinessCalendar(time.UTC, "09:00", "18:00", time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+	require.NoError(t, err, "NewBusinessCalendar() error")
+	require.NoError(t, calendar.SetHours(time.Friday, "09:00", "13:00", "14:00", "18:00"), "calendar.SetHours() error")
+	require.NoError(t, calendar.AddHolidays("2026-01-01"), "calendar.AddHolidays() error")
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     time.Duration
+	}{
+		{"Within a day", time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC), time.Date(2026, 1, 5, 10, 30, 0, 0, time.UTC), 30 * time.Minute},
+		{"Before opening", time.Date(2026, 1, 5, 7, 0, 0, 0, time.UTC), time.Date(2026, 1, 5, 9, 15, 0, 0, time.UTC), 15 * time.Minute},
+		{"Overnight", time.Date(2026, 1, 5, 17, 0, 0, 0, time.UTC), time.Date(2026, 1, 6, 10, 0, 0, 0, time.UTC), 2 * time.Hour},
+		{"Friday break", time.Date(2026, 1, 9, 12, 0, 0, 0, time.UTC), time.Date(2026, 1, 9, 15, 0, 0, 0, time.UTC), 2 * time.Hour},
+		{"Weekend", time.Date(2026, 1, 9, 17, 0, 0, 0, time.UTC), time.Date(2026, 1, 12, 10, 0, 0, 0, time.UTC), 2 * time.Hour},
+		{"Holiday", time.Date(2025, 12, 31, 17, 0, 0, 0, time.UTC), time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC), 2 * time.Hour},
+		{"Reversed", time.Date(2026, 1, 5, 11, 0, 0, 0, time.UTC), time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, calendar.WorkingDuration(tt.from, tt.to), "calendar.WorkingDuration()")
+		})
+	}
+}
+
+func TestBusinessCalendar_WorkingDurationDST(t *testing.T) {
+	location, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err, "time.LoadLocation() error")
+
+	calendar, err := NewBusinessCalendar(location, "09:00", "18:00", time.Sunday, time.Monday)
+	require.NoError(t, err, "NewBusinessCalendar() error")
+
+	// Clocks go forward at 02:00 on 2026-03-29 and back at 03:00 on 2026-10-25
+	spring := calendar.WorkingDuration(time.Date(2026, 3, 29, 0, 0, 0, 0, location), time.Date(2026, 3, 29, 12, 0, 0, 0, location))
+	require.Equal(t, 3*time.Hour, spring, "the window should open at 09:00 after the spring transition")
+	autumn := calendar.WorkingDuration(time.Date(2026, 10, 25, 0, 0, 0, 0, location), time.Date(2026, 10, 25, 12, 0, 0, 0, location))
+	require.Equal(t, 3*time.Hour, autumn, "the window should open at 09:00 after the autumn transition")
+	require.True(t, calendar.IsOpen(time.Date(2026, 3, 29, 9, 30, 0, 0, location)), "calendar.IsOpen() should use the wall clock")
+}
@@ -3,6 +3,7 @@ package ctd
 import (
 	"context"
 	"fmt"
+	"slices"
 )
 
 // Channel represents a single channel in the Chat2Desk API
@@ -93,3 +94,142 @@ func (dst *Ctd) GetChannels(ctx context.Context, offset, limit int) ([]Channel,
 
 	return response.Data, response.Meta.Total, nil
 }
+
+// GetTransports returns the transports of the channel, including the ones missing from AllTransports.
+// Use TransportsWith to get only the transports supporting a capability.
+//
+// Returns:
+//   - A slice of Transport.
+func (dst *Channel) GetTransports() []Transport {
+	result := make([]Transport, 0, len(dst.Transports))
+	for _, item := range dst.Transports {
+		if transport, err := ParseTransport(item); err == nil {
+			result = append(result, transport)
+		}
+	}
+	return result
+}
+
+// HasTransport reports whether the channel supports the transport.
+// Use HasCapability to check the features of the transports.
+func (dst *Channel) HasTransport(transport Transport) bool {
+	return slices.Contains(dst.GetTransports(), transport)
+}
+
+// AllChannels retrieves all channels from the Chat2Desk API by handling pagination.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//
+// Returns:
+//   - A slice of Channel containing all the channels.
+//   - An error if the request fails or if the response is invalid.
+func (dst *Ctd) AllChannels(ctx context.Context) ([]Channel, error) {
+	channels := []Channel{}
+	offset := 0
+	limit := 100
+
+	for {
+		data, _, err := dst.GetChannels(ctx, offset, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		channels = append(channels, data...)
+		if len(data) < limit {
+			break
+		}
+
+		offset += limit
+	}
+
+	return channels, nil
+}
+
+// GetChannel retrieves a channel by its ID.
+// The API has no endpoint for a single channel, so the channel is looked up in the channel list.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the channel.
+//
+// Returns:
+//   - A pointer to a Channel.
+//   - ErrorInvalidChannelID if the channel is not found, or an error if the request fails.
+func (dst *Ctd) GetChannel(ctx context.Context, id int) (*Channel, error) {
+	channels, err := dst.AllChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range channels {
+		if channels[i].ID == id {
+			return &channels[i], nil
+		}
+	}
+
+	return nil, ErrorInvalidChannelID
+}
+
+// FilterChannels returns the channels supporting the transport.
+// Use FilterChannelsWith to filter the channels by a capability of their transports.
+//
+// Parameters:
+//   - channels: The channels.
+//   - transport: The transport.
+//
+// Returns:
+//   - A slice of Channel supporting the transport.
+func FilterChannels(channels []Channel, transport Transport) []Channel {
+	result := []Channel{}
+	for i := range channels {
+		if channels[i].HasTransport(transport) {
+			result = append(result, channels[i])
+		}
+	}
+	return result
+}
+
+// ChannelsForTransport retrieves all channels supporting the transport.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - transport: The transport.
+//
+// Returns:
+//   - A slice of Channel supporting the transport.
+//   - ErrorInvalidTransport if the transport is empty, or an error if the request fails.
+func (dst *Ctd) ChannelsForTransport(ctx context.Context, transport Transport) ([]Channel, error) {
+	if transport == "" {
+		return nil, ErrorInvalidTransport
+	}
+
+	channels, err := dst.AllChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return FilterChannels(channels, transport), nil
+}
+
+// ChannelIDForTransport returns the ID of the first channel supporting the transport,
+// to be used with CreateClient and SendMessage.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - transport: The transport.
+//
+// Returns:
+//   - The ID of the channel.
+//   - ErrorInvalidTransport if the transport is empty or no channel supports it, or an error if the request fails.
+func (dst *Ctd) ChannelIDForTransport(ctx context.Context, transport Transport) (int, error) {
+	channels, err := dst.ChannelsForTransport(ctx, transport)
+	if err != nil {
+		return 0, err
+	}
+	if len(channels) == 0 {
+		return 0, ErrorInvalidTransport
+	}
+
+	return channels[0].ID, nil
+}
@@ -0,0 +1,217 @@
+// Command ctd-relay receives Chat2Desk webhooks and forwards them to several downstream subscribers.
+//
+// Usage:
+//
+//	ctd-relay -config relay.json
+//
+// The configuration file is a JSON object:
+//
+//	{
+//	  "listen": ":8080",
+//	  "path": "/webhook",
+//	  "secret": "shared secret from the webhook URL",
+//	  "allowed_ips": ["10.0.0.0/8"],
+//	  "trust_proxy": false,
+//	  "proxies": ["192.168.0.0/16"],
+//	  "admin_listen": "127.0.0.1:8081",
+//	  "journal_dir": "/var/lib/ctd-relay/journal",
+//	  "subscribers": [
+//	    {"name": "crm", "url": "https://crm.local/chat2desk", "events": ["inbox"], "channels": [123], "retry_delay": "2s", "timeout": "5s"}
+//	  ]
+//	}
+//
+// The secret can also be set with the CTD_RELAY_SECRET environment variable. The relay refuses to start
+// without a secret or an IP allowlist unless "insecure" is true.
+// The delivery log is written to the standard error. If the admin address is set, the delivery log is
+// also available as JSON at /deliveries on that address; it is not served on the public listener.
+// If the journal directory is set, every received webhook is recorded there and can be replayed with ctd-replay.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ra-company/ctd"
+)
+
+type subscriber struct {
+	ctd.RelaySubscriber
+	RetryDelay string `json:"retry_delay"` // RetryDelay: Delay before the first retry (e.g., 1s)
+	Timeout    string `json:"timeout"`     // Timeout: Request timeout (e.g., 10s)
+}
+
+type config struct {
+	Listen      string       `json:"listen"`       // Listen: Address to listen on (default: :8080)
+	Path        string       `json:"path"`         // Path: Path of the webhook endpoint (default: /webhook)
+	Secret      string       `json:"secret"`       // Secret: Shared secret expected in the webhook URL
+	AllowedIPs  []string     `json:"allowed_ips"`  // AllowedIPs: Allowed sender IP addresses or CIDR ranges
+	TrustProxy  bool         `json:"trust_proxy"`  // TrustProxy: Take the sender IP from the proxy headers
+	Proxies     []string     `json:"proxies"`      // Proxies: Proxy hops skipped in X-Forwarded-For, besides the nearest proxy
+	Insecure    bool         `json:"insecure"`     // Insecure: Allow starting without a secret and an IP allowlist
+	AdminListen string       `json:"admin_listen"` // AdminListen: Address serving /deliveries (empty - not served)
+	JournalDir  string       `json:"journal_dir"`  // JournalDir: Directory of the event journal (empty - no journal)
+	Subscribers []subscriber `json:"subscribers"`  // Subscribers: Downstream subscribers
+}
+
+func main() {
+	path := flag.String("config", "relay.json", "path to the configuration file")
+	flag.Parse()
+
+	cfg, err := loadConfig(*path)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	if err := run(cfg); err != nil {
+		log.Fatalf("Relay stopped: %v", err)
+	}
+}
+
+// run serves the webhooks until a signal is received or a server fails, then stops the relay gracefully.
+func run(cfg *config) error {
+	subscribers := make([]ctd.RelaySubscriber, 0, len(cfg.Subscribers))
+	var err error
+	for _, item := range cfg.Subscribers {
+		if item.RelaySubscriber.RetryDelay, err = parseDuration(item.RetryDelay); err != nil {
+			return fmt.Errorf("invalid retry delay of subscriber %s: %w", item.Name, err)
+		}
+		if item.RelaySubscriber.Timeout, err = parseDuration(item.Timeout); err != nil {
+			return fmt.Errorf("invalid timeout of subscriber %s: %w", item.Name, err)
+		}
+		subscribers = append(subscribers, item.RelaySubscriber)
+	}
+
+	relay, err := ctd.NewRelay(subscribers)
+	if err != nil {
+		return fmt.Errorf("failed to create relay: %w", err)
+	}
+	relay.OnDelivery = func(delivery ctd.RelayDelivery) {
+		if delivery.Error != nil {
+			log.Printf("FAILED %s %s -> %s: attempts=%d status=%d error=%v", delivery.HookType, delivery.Key, delivery.Subscriber, delivery.Attempts, delivery.StatusCode, delivery.Error)
+			return
+		}
+		log.Printf("OK %s %s -> %s: attempts=%d status=%d duration=%s", delivery.HookType, delivery.Key, delivery.Subscriber, delivery.Attempts, delivery.StatusCode, delivery.Duration)
+	}
+
+	verifier := ctd.NewWebhookVerifier(cfg.Secret)
+	verifier.AllowedIPs = cfg.AllowedIPs
+	verifier.TrustProxy = cfg.TrustProxy
+	verifier.Proxies = cfg.Proxies
+
+	handler := relay.Relay
+	if cfg.JournalDir != "" {
+		journal, err := ctd.NewFileJournal(cfg.JournalDir)
+		if err != nil {
+			return fmt.Errorf("failed to open journal: %w", err)
+		}
+		defer journal.Close()
+		handler = ctd.Journaled(journal, relay.Relay)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("POST "+cfg.Path, verifier.Handler(handler))
+	servers := []*http.Server{{Addr: cfg.Listen, Handler: mux}}
+
+	if cfg.AdminListen != "" {
+		admin := http.NewServeMux()
+		admin.HandleFunc("GET /deliveries", func(w http.ResponseWriter, r *http.Request) {
+			deliveries := relay.Deliveries()
+			items := make([]map[string]any, 0, len(deliveries))
+			for _, delivery := range deliveries {
+				item := map[string]any{
+					"subscriber":  delivery.Subscriber,
+					"key":         delivery.Key,
+					"hook_type":   delivery.HookType,
+					"attempts":    delivery.Attempts,
+					"status_code": delivery.StatusCode,
+					"duration":    delivery.Duration.String(),
+					"time":        delivery.Time,
+				}
+				if delivery.Error != nil {
+					item["error"] = delivery.Error.Error()
+				}
+				items = append(items, item)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{"pending": relay.Pending(), "deliveries": items})
+		})
+		servers = append(servers, &http.Server{Addr: cfg.AdminListen, Handler: admin})
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	failed := make(chan error, len(servers))
+	for _, server := range servers {
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				failed <- fmt.Errorf("failed to serve %s: %w", server.Addr, err)
+			}
+		}()
+	}
+	log.Printf("Relaying webhooks from %s%s to %d subscribers", cfg.Listen, cfg.Path, len(subscribers))
+
+	select {
+	case <-ctx.Done():
+	case err = <-failed:
+	}
+
+	shutdown, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, server := range servers {
+		if err := server.Shutdown(shutdown); err != nil {
+			log.Printf("Failed to stop server %s: %v", server.Addr, err)
+		}
+	}
+	if err := relay.Shutdown(shutdown); err != nil {
+		log.Printf("Failed to deliver queued webhooks: %v", err)
+	}
+
+	return err
+}
+
+// loadConfig reads the configuration file and applies the defaults.
+func loadConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := config{}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.Listen == "" {
+		cfg.Listen = ":8080"
+	}
+	if cfg.Path == "" {
+		cfg.Path = "/webhook"
+	}
+	if secret := os.Getenv("CTD_RELAY_SECRET"); secret != "" {
+		cfg.Secret = secret
+	}
+	if cfg.Secret == "" && len(cfg.AllowedIPs) == 0 && !cfg.Insecure {
+		return nil, errors.New("neither secret nor allowed_ips is set; set \"insecure\": true to accept unverified webhooks")
+	}
+
+	return &cfg, nil
+}
+
+// parseDuration parses an optional duration.
+func parseDuration(str string) (time.Duration, error) {
+	if str == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(str)
+}
@@ -0,0 +1,98 @@
+// Command ctd-replay re-sends Chat2Desk webhooks recorded in the event journal to an HTTP endpoint.
+//
+// Usage:
+//
+//	ctd-replay -journal /var/lib/ctd-relay/journal -target https://crm.local/chat2desk \
+//	  -from 2026-01-02T00:00:00Z -to 2026-01-03T00:00:00Z -events inbox,outbox
+//
+// Without -target the selected events are printed to the standard output as NDJSON.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ra-company/ctd"
+)
+
+func main() {
+	dir := flag.String("journal", "journal", "directory of the event journal")
+	target := flag.String("target", "", "URL the events are posted to (empty - print the events)")
+	from := flag.String("from", "", "start of the time range, inclusive (RFC3339 or YYYY-MM-DD)")
+	to := flag.String("to", "", "end of the time range, exclusive (RFC3339 or YYYY-MM-DD)")
+	events := flag.String("events", "", "comma-separated hook types to replay (empty - all)")
+	header := flag.String("header", "", "additional request header (Name: value)")
+	timeout := flag.Duration("timeout", 10*time.Second, "request timeout")
+	flag.Parse()
+
+	filter := ctd.JournalFilter{}
+	var err error
+	if filter.From, err = parseTime(*from); err != nil {
+		log.Fatalf("Invalid start of the time range: %v", err)
+	}
+	if filter.To, err = parseTime(*to); err != nil {
+		log.Fatalf("Invalid end of the time range: %v", err)
+	}
+	if *events != "" {
+		filter.HookTypes = strings.Split(*events, ",")
+	}
+
+	// NewFileJournal creates a missing directory, which would hide a mistyped path
+	if info, err := os.Stat(*dir); err != nil {
+		log.Fatalf("Failed to open journal: %v", err)
+	} else if !info.IsDir() {
+		log.Fatalf("Failed to open journal: %s is not a directory", *dir)
+	}
+
+	journal, err := ctd.NewFileJournal(*dir)
+	if err != nil {
+		log.Fatalf("Failed to open journal: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	handler := printNotification
+	if *target != "" {
+		headers := map[string]string{}
+		if name, value, ok := strings.Cut(*header, ":"); ok {
+			headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+		handler = ctd.ReplayToURL(*target, headers, *timeout)
+	}
+
+	count, err := ctd.ReplayJournal(ctx, journal, filter, handler)
+	if err != nil {
+		log.Fatalf("Replayed %d events, stopped: %v", count, err)
+	}
+	log.Printf("Replayed %d events", count)
+}
+
+// printNotification writes the notification body to the standard output.
+func printNotification(ctx context.Context, notification *ctd.WebhookNotification) error {
+	data, err := json.Marshal(notification.Raw)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Println(string(data))
+	return err
+}
+
+// parseTime parses an optional time given as RFC3339 or date.
+func parseTime(str string) (time.Time, error) {
+	if str == "" {
+		return time.Time{}, nil
+	}
+	if result, err := time.Parse(time.RFC3339, str); err == nil {
+		return result, nil
+	}
+	return time.Parse("2006-01-02", str)
+}
@@ -0,0 +1,390 @@
+package ctd
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ra-company/params"
+)
+
+const (
+	ExportCSV    = "csv"    // Comma-separated values with a header row
+	ExportTSV    = "tsv"    // Tab-separated values with a header row; tabs and line breaks in values are replaced with spaces
+	ExportNDJSON = "ndjson" // One JSON object per line
+)
+
+// ExportOptions configures an Exporter.
+type ExportOptions struct {
+	Format     string            // Format: Output format ('csv', 'tsv' or 'ndjson', default: 'csv')
+	Columns    []string          // Columns: Exported columns in order, by JSON name or field name (empty - all columns)
+	Headers    map[string]string // Headers: Localized header titles by JSON name or field name (missing - the column name)
+	NoHeader   bool              // NoHeader: Don't write the header row of CSV and TSV
+	TimeLayout string            // TimeLayout: Layout of time values (default: RFC3339)
+	Location   *time.Location    // Location: Time zone of time values (nil - unchanged)
+}
+
+// tsvReplacer removes the separators from the TSV values.
+var tsvReplacer = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+// exportColumn is a column of the exported struct.
+type exportColumn struct {
+	name  string // JSON name of the field
+	field string // Go name of the field
+	index []int
+}
+
+// Exporter streams rows of the package collections (statistics rows, operators, clients, dialogs, tags, etc.)
+// to an io.Writer. The columns are the exported struct fields named by their JSON tags; nested structs,
+// slices and maps are written as JSON and durations as seconds. Rows are written as they come, so
+// collections of any size can be exported. All the rows must have the same type. Time values, including
+// the API time types, are written with the layout and time zone of the options.
+type Exporter struct {
+	options ExportOptions
+	writer  *bufio.Writer
+	csv     *csv.Writer
+	rowType reflect.Type
+	columns []exportColumn
+}
+
+// NewExporter creates a new Exporter writing to the writer.
+//
+// Parameters:
+//   - w: The destination.
+//   - options: The export options (nil - CSV with all columns).
+//
+// Returns:
+//   - A pointer to an Exporter.
+//   - ErrorInvalidParameters if the format is unknown.
+func NewExporter(w io.Writer, options *ExportOptions) (*Exporter, error) {
+	opts := ExportOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Format == "" {
+		opts.Format = ExportCSV
+	}
+	if opts.TimeLayout == "" {
+		opts.TimeLayout = time.RFC3339
+	}
+
+	exporter := &Exporter{options: opts, writer: bufio.NewWriter(w)}
+	switch opts.Format {
+	case ExportCSV:
+		exporter.csv = csv.NewWriter(exporter.writer)
+	case ExportTSV, ExportNDJSON:
+	default:
+		return nil, ErrorInvalidParameters
+	}
+
+	return exporter, nil
+}
+
+// Write writes a row. The first row defines the columns and writes the header.
+//
+// Parameters:
+//   - row: A struct or a pointer to a struct.
+//
+// Returns:
+//   - ErrorInvalidParameters if the row isn't a struct, its type differs from the first row, or a column is unknown.
+//   - An error if writing fails.
+func (dst *Exporter) Write(row any) error {
+	value := reflect.ValueOf(row)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return ErrorInvalidParameters
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return ErrorInvalidParameters
+	}
+
+	if dst.rowType == nil {
+		if err := dst.init(value.Type()); err != nil {
+			return err
+		}
+	} else if value.Type() != dst.rowType {
+		return ErrorInvalidParameters
+	}
+
+	if dst.options.Format == ExportNDJSON {
+		return dst.writeJSON(value)
+	}
+
+	record := make([]string, len(dst.columns))
+	for i, column := range dst.columns {
+		record[i] = dst.format(value.FieldByIndex(column.index))
+	}
+	return dst.writeRecord(record)
+}
+
+// Flush writes the buffered data to the destination.
+//
+// Returns:
+//   - An error if writing fails.
+func (dst *Exporter) Flush() error {
+	if dst.csv != nil {
+		dst.csv.Flush()
+		if err := dst.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return dst.writer.Flush()
+}
+
+// Export writes the rows to the writer and flushes it.
+//
+// Parameters:
+//   - w: The destination.
+//   - rows: The rows.
+//   - options: The export options (nil - CSV with all columns).
+//
+// Returns:
+//   - An error if the options or the rows are invalid or writing fails.
+func Export[T any](w io.Writer, rows []T, options *ExportOptions) error {
+	exporter, err := NewExporter(w, options)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if err := exporter.Write(row); err != nil {
+			return err
+		}
+	}
+
+	if err := exporter.initEmpty(reflect.TypeFor[T]()); err != nil {
+		return err
+	}
+
+	return exporter.Flush()
+}
+
+// ExportSeq writes the rows of an iterator, such as StatisticsRows, to the writer and flushes it.
+// The export stops at the first error of the iterator.
+//
+// Parameters:
+//   - w: The destination.
+//   - rows: The iterator over the rows and errors.
+//   - options: The export options (nil - CSV with all columns).
+//
+// Returns:
+//   - The number of rows written.
+//   - An error if the options or the rows are invalid, the iterator fails or writing fails.
+func ExportSeq[T any](w io.Writer, rows iter.Seq2[T, error], options *ExportOptions) (int, error) {
+	exporter, err := NewExporter(w, options)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for row, err := range rows {
+		if err != nil {
+			exporter.Flush()
+			return count, err
+		}
+		if err := exporter.Write(row); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	if err := exporter.initEmpty(reflect.TypeFor[T]()); err != nil {
+		return count, err
+	}
+
+	return count, exporter.Flush()
+}
+
+// init resolves the columns of the row type and writes the header.
+func (dst *Exporter) init(rowType reflect.Type) error {
+	all := []exportColumn{}
+	collectExportColumns(rowType, nil, &all)
+
+	columns := all
+	if len(dst.options.Columns) > 0 {
+		columns = make([]exportColumn, 0, len(dst.options.Columns))
+		for _, name := range dst.options.Columns {
+			found := false
+			for _, column := range all {
+				if column.name == name || column.field == name {
+					columns = append(columns, column)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("%w: unknown column %s", ErrorInvalidParameters, name)
+			}
+		}
+	}
+
+	dst.rowType = rowType
+	dst.columns = columns
+
+	if dst.options.Format == ExportNDJSON || dst.options.NoHeader {
+		return nil
+	}
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+		if title, ok := dst.options.Headers[column.name]; ok {
+			header[i] = title
+		} else if title, ok := dst.options.Headers[column.field]; ok {
+			header[i] = title
+		}
+	}
+	return dst.writeRecord(header)
+}
+
+// initEmpty resolves the columns from the row type if no rows were written, so the header is still written.
+// Rows of interface types are skipped, as their columns are unknown.
+func (dst *Exporter) initEmpty(rowType reflect.Type) error {
+	if dst.rowType != nil {
+		return nil
+	}
+
+	for rowType.Kind() == reflect.Pointer {
+		rowType = rowType.Elem()
+	}
+	if rowType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return dst.init(rowType)
+}
+
+// writeRecord writes a CSV or TSV record.
+func (dst *Exporter) writeRecord(record []string) error {
+	if dst.csv != nil {
+		return dst.csv.Write(record)
+	}
+
+	for i, value := range record {
+		record[i] = tsvReplacer.Replace(value)
+	}
+	_, err := dst.writer.WriteString(strings.Join(record, "\t") + "\n")
+	return err
+}
+
+// writeJSON writes the selected columns of the row as a JSON object.
+func (dst *Exporter) writeJSON(value reflect.Value) error {
+	buf := []byte{'{'}
+	for i, column := range dst.columns {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		key, _ := json.Marshal(column.name)
+		buf = append(buf, key...)
+		buf = append(buf, ':')
+
+		field := value.FieldByIndex(column.index)
+		var item any = field.Interface()
+		switch v := item.(type) {
+		case time.Time:
+			item = dst.formatTime(v)
+		case params.Time:
+			item = dst.formatTime(v.Time)
+		case time.Duration:
+			item = v.Seconds()
+		}
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, data...)
+	}
+	buf = append(buf, '}', '\n')
+
+	_, err := dst.writer.Write(buf)
+	return err
+}
+
+// format converts a field value to text.
+func (dst *Exporter) format(field reflect.Value) string {
+	switch v := field.Interface().(type) {
+	case time.Time:
+		return dst.formatTime(v)
+	case params.Time:
+		return dst.formatTime(v.Time)
+	case time.Duration:
+		return strconv.FormatFloat(v.Seconds(), 'f', -1, 64)
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		return field.String()
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(field.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(field.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(field.Float(), 'f', -1, 64)
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		if field.IsNil() {
+			return ""
+		}
+	}
+
+	// Other values are written as JSON; JSON strings are unquoted
+	data, err := json.Marshal(field.Interface())
+	if err != nil {
+		return ""
+	}
+	var str string
+	if json.Unmarshal(data, &str) == nil {
+		return str
+	}
+	return string(data)
+}
+
+// formatTime formats a time value with the layout and time zone of the options.
+func (dst *Exporter) formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	if dst.options.Location != nil {
+		t = t.In(dst.options.Location)
+	}
+	return t.Format(dst.options.TimeLayout)
+}
+
+// collectExportColumns appends the exported fields of the struct type, flattening the embedded structs.
+func collectExportColumns(rowType reflect.Type, index []int, columns *[]exportColumn) {
+	for i := range rowType.NumField() {
+		field := rowType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			tag, _, _ = strings.Cut(tag, ",")
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			collectExportColumns(field.Type, fieldIndex, columns)
+			continue
+		}
+
+		*columns = append(*columns, exportColumn{name: name, field: field.Name, index: fieldIndex})
+	}
+}
@@ -0,0 +1,133 @@
+package ctd
+
+import (
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ra-company/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	operators := []Operator{
+		{ID: 1, FirstName: "Anna", LastName: "Ivanova, Jr.", Online: 1},
+		{ID: 2, FirstName: "Oleg\tPetrov", Role: "admin"},
+	}
+
+	t.Run("CSV with columns and headers", func(t *testing.T) {
+		out := strings.Builder{}
+		err := Export(&out, operators, &ExportOptions{
+			Columns: []string{"id", "last_name", "online"},
+			Headers: map[string]string{"id": "ИД", "last_name": "Фамилия"},
+		})
+		require.NoError(t, err, "Export() error")
+		require.Equal(t, "ИД,Фамилия,online\n1,\"Ivanova, Jr.\",1\n2,,0\n", out.String(), "CSV output")
+	})
+
+	t.Run("TSV", func(t *testing.T) {
+		out := strings.Builder{}
+		err := Export(&out, operators, &ExportOptions{Format: ExportTSV, Columns: []string{"id", "first_name"}, NoHeader: true})
+		require.NoError(t, err, "Export() error")
+		require.Equal(t, "1\tAnna\n2\tOleg Petrov\n", out.String(), "TSV output")
+	})
+
+	t.Run("NDJSON with times and durations", func(t *testing.T) {
+		location := time.FixedZone("UTC+3", 3*60*60)
+		rows := []*RequestSLA{{
+			RequestID:     5,
+			Start:         time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+			FirstResponse: 90 * time.Second,
+			ResponseTimes: []time.Duration{90 * time.Second},
+		}}
+		out := strings.Builder{}
+		err := Export(&out, rows, &ExportOptions{
+			Format:     ExportNDJSON,
+			Columns:    []string{"RequestID", "Start", "End", "FirstResponse"},
+			TimeLayout: "2006-01-02 15:04",
+			Location:   location,
+		})
+		require.NoError(t, err, "Export() error")
+		require.Equal(t, `{"RequestID":5,"Start":"2026-01-05 12:00","End":"","FirstResponse":90}`+"\n", out.String(), "NDJSON output")
+
+		out.Reset()
+		require.NoError(t, Export(&out, rows, &ExportOptions{Columns: []string{"FirstResponse", "ResponseTimes"}}), "Export() error")
+		require.Equal(t, "FirstResponse,ResponseTimes\n90,[90000000000]\n", out.String(), "nested values should be written as JSON")
+	})
+
+	t.Run("embedded structs", func(t *testing.T) {
+		rows := []OperatorRatingSummary{{OperatorID: 7, Name: "Anna", RatingSummary: RatingSummary{Count: 2, CSAT: 50}}}
+		out := strings.Builder{}
+		require.NoError(t, Export(&out, rows, &ExportOptions{Columns: []string{"OperatorID", "Count", "CSAT"}}), "Export() error")
+		require.Equal(t, "OperatorID,Count,CSAT\n7,2,50\n", out.String(), "embedded fields should be flattened")
+	})
+
+	t.Run("columns by field name and API times", func(t *testing.T) {
+		dialogs := []Dialog{{ID: 3}}
+		out := strings.Builder{}
+		err := Export(&out, dialogs, &ExportOptions{
+			Columns: []string{"ID", "begin", "End"},
+			Headers: map[string]string{"ID": "Dialog", "end": "Closed"},
+		})
+		require.NoError(t, err, "Export() error")
+		require.Equal(t, "Dialog,begin,Closed\n3,,\n", out.String(), "columns should match both names and empty API times should be blank")
+
+		out.Reset()
+		require.NoError(t, Export(&out, dialogs, &ExportOptions{Format: ExportNDJSON, Columns: []string{"id", "begin"}}), "Export() error")
+		require.Equal(t, `{"id":3,"begin":""}`+"\n", out.String(), "empty API times should be blank in NDJSON")
+
+		out.Reset()
+		dialogs[0].Begin = params.Time{Time: time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)}
+		err = Export(&out, dialogs, &ExportOptions{Format: ExportNDJSON, Columns: []string{"id", "begin"}, TimeLayout: "2006-01-02 15:04", Location: time.FixedZone("UTC+3", 3*60*60)})
+		require.NoError(t, err, "Export() error")
+		require.Equal(t, `{"id":3,"begin":"2026-01-05 12:00"}`+"\n", out.String(), "API times should use the layout and the location")
+	})
+
+	t.Run("empty collection", func(t *testing.T) {
+		out := strings.Builder{}
+		require.NoError(t, Export(&out, []*Operator{}, &ExportOptions{Columns: []string{"id", "LastName"}}), "Export() error")
+		require.Equal(t, "id,last_name\n", out.String(), "the header should be written without rows")
+
+		out.Reset()
+		count, err := ExportSeq(&out, func(yield func(StatisticsTag, error) bool) {}, &ExportOptions{Columns: []string{"tag_id"}})
+		require.NoError(t, err, "ExportSeq() error")
+		require.Zero(t, count, "no rows should be written")
+		require.Equal(t, "tag_id\n", out.String(), "the header should be written without rows")
+
+		out.Reset()
+		require.NoError(t, Export(&out, []Operator{}, &ExportOptions{Format: ExportNDJSON}), "Export() error")
+		require.Empty(t, out.String(), "NDJSON has no header")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewExporter(&strings.Builder{}, &ExportOptions{Format: "xlsx"})
+		require.ErrorIs(t, err, ErrorInvalidParameters, "unknown format should be rejected")
+
+		err = Export(&strings.Builder{}, operators, &ExportOptions{Columns: []string{"missing"}})
+		require.ErrorIs(t, err, ErrorInvalidParameters, "unknown column should be rejected")
+
+		err = Export(&strings.Builder{}, []any{operators[0], Tag{}}, nil)
+		require.ErrorIs(t, err, ErrorInvalidParameters, "rows of different types should be rejected")
+	})
+}
+
+func TestExportSeq(t *testing.T) {
+	failure := errors.New("page failed")
+	rows := iter.Seq2[StatisticsTag, error](func(yield func(StatisticsTag, error) bool) {
+		if !yield(StatisticsTag{TagID: 1, Name: "vip", Requests: "10"}, nil) {
+			return
+		}
+		if !yield(StatisticsTag{TagID: 2, Name: "spam"}, nil) {
+			return
+		}
+		yield(StatisticsTag{}, failure)
+	})
+
+	out := strings.Builder{}
+	count, err := ExportSeq(&out, rows, &ExportOptions{Format: ExportNDJSON, Columns: []string{"tag_id", "requests"}})
+	require.ErrorIs(t, err, failure, "iterator error should be returned")
+	require.Equal(t, 2, count, "rows written before the error")
+	require.Equal(t, "{\"tag_id\":1,\"requests\":10}\n{\"tag_id\":2,\"requests\":0}\n", out.String(), "rows written before the error should be flushed")
+}
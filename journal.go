@@ -0,0 +1,293 @@
+package ctd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JournalEntry is a webhook notification recorded in the event journal.
+type JournalEntry struct {
+	Time     time.Time       `json:"time"`      // Time: Time when the notification was received
+	Key      string          `json:"key"`       // Key: Key of the notification
+	HookType string          `json:"hook_type"` // HookType: Hook type of the notification
+	Body     json.RawMessage `json:"body"`      // Body: Original body of the webhook request
+}
+
+// NewJournalEntry creates a journal entry for the notification.
+//
+// Parameters:
+//   - notification: The webhook notification.
+//   - at: The time when the notification was received.
+//
+// Returns:
+//   - A JournalEntry.
+func NewJournalEntry(notification *WebhookNotification, at time.Time) JournalEntry {
+	return JournalEntry{
+		Time:     at,
+		Key:      notification.Key(),
+		HookType: notification.HookType,
+		Body:     notification.Raw,
+	}
+}
+
+// Notification decodes the recorded notification.
+func (dst *JournalEntry) Notification() (*WebhookNotification, error) {
+	return ParseWebhookNotification(dst.Body)
+}
+
+// JournalFilter selects the journal entries.
+type JournalFilter struct {
+	From      time.Time // From: Start of the time range, inclusive (zero - no limit)
+	To        time.Time // To: End of the time range, exclusive (zero - no limit)
+	HookTypes []string  // HookTypes: Hook types to select (empty - all)
+}
+
+// Matches reports whether the entry is selected by the filter.
+func (dst *JournalFilter) Matches(entry *JournalEntry) bool {
+	if !dst.From.IsZero() && entry.Time.Before(dst.From) {
+		return false
+	}
+	if !dst.To.IsZero() && !entry.Time.Before(dst.To) {
+		return false
+	}
+	if len(dst.HookTypes) > 0 && !slices.Contains(dst.HookTypes, entry.HookType) {
+		return false
+	}
+	return true
+}
+
+// JournalStore is an append-only storage of webhook notifications.
+// Implementations must be safe for concurrent use.
+type JournalStore interface {
+	// Append records the entry.
+	Append(ctx context.Context, entry JournalEntry) error
+	// Read calls fn for every entry matching the filter in the order they were recorded.
+	// Reading stops at the first error returned by fn.
+	Read(ctx context.Context, filter JournalFilter, fn func(entry JournalEntry) error) error
+}
+
+// FileJournal is a JournalStore writing the entries to daily NDJSON files (events-YYYY-MM-DD.ndjson, UTC dates).
+type FileJournal struct {
+	Dir string // Dir: Directory of the journal files
+
+	mu   sync.Mutex
+	day  string
+	file *os.File
+}
+
+// NewFileJournal creates a new FileJournal, creating the directory if it doesn't exist.
+//
+// Parameters:
+//   - dir: The directory of the journal files.
+//
+// Returns:
+//   - A pointer to a FileJournal.
+//   - An error if the directory can't be created.
+func NewFileJournal(dir string) (*FileJournal, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileJournal{Dir: dir}, nil
+}
+
+// Append writes the entry to the file of its day.
+func (dst *FileJournal) Append(ctx context.Context, entry JournalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	day := entry.Time.UTC().Format("2006-01-02")
+	if dst.file == nil || dst.day != day {
+		if dst.file != nil {
+			dst.file.Close()
+		}
+		file, err := os.OpenFile(filepath.Join(dst.Dir, fmt.Sprintf("events-%s.ndjson", day)), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			dst.file = nil
+			return err
+		}
+		dst.file = file
+		dst.day = day
+	}
+
+	_, err = dst.file.Write(data)
+	return err
+}
+
+// Read calls fn for every entry matching the filter. Files outside the time range are skipped.
+func (dst *FileJournal) Read(ctx context.Context, filter JournalFilter, fn func(entry JournalEntry) error) error {
+	files, err := filepath.Glob(filepath.Join(dst.Dir, "events-*.ndjson"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	for _, name := range files {
+		day, err := time.Parse("2006-01-02", strings.TrimSuffix(strings.TrimPrefix(filepath.Base(name), "events-"), ".ndjson"))
+		if err != nil {
+			continue
+		}
+		if !filter.From.IsZero() && !day.Add(24*time.Hour).After(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !day.Before(filter.To) {
+			continue
+		}
+
+		if err := dst.readFile(ctx, name, &filter, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close closes the current journal file.
+func (dst *FileJournal) Close() error {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	if dst.file == nil {
+		return nil
+	}
+	err := dst.file.Close()
+	dst.file = nil
+	return err
+}
+
+// readFile reads the entries of a single journal file.
+func (dst *FileJournal) readFile(ctx context.Context, name string, filter *JournalFilter, fn func(entry JournalEntry) error) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		entry := JournalEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// Skip the line broken by an interrupted write
+			continue
+		}
+		if !filter.Matches(&entry) {
+			continue
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Journaled returns a webhook callback recording every notification in the journal before passing it to next.
+// If the notification can't be recorded, it is not passed and the error is returned, so Chat2Desk repeats it.
+//
+// Parameters:
+//   - store: The journal store.
+//   - next: The callback processing the notifications.
+//
+// Returns:
+//   - The webhook callback.
+func Journaled(store JournalStore, next func(ctx context.Context, notification *WebhookNotification) error) func(ctx context.Context, notification *WebhookNotification) error {
+	return func(ctx context.Context, notification *WebhookNotification) error {
+		if err := store.Append(ctx, NewJournalEntry(notification, time.Now())); err != nil {
+			return err
+		}
+		return next(ctx, notification)
+	}
+}
+
+// ReplayJournal passes the recorded notifications matching the filter to the handler again.
+//
+// Parameters:
+//   - ctx: The context for the replay, allowing for cancellation and timeouts.
+//   - store: The journal store.
+//   - filter: The filter selecting the entries.
+//   - handler: The function processing the notifications (e.g., ReplayToURL).
+//     Note that a WebhookDispatcher drops the notifications it has already seen.
+//
+// Returns:
+//   - The number of the replayed notifications.
+//   - The first error of the handler or of the store.
+func ReplayJournal(ctx context.Context, store JournalStore, filter JournalFilter, handler func(ctx context.Context, notification *WebhookNotification) error) (int, error) {
+	count := 0
+	err := store.Read(ctx, filter, func(entry JournalEntry) error {
+		notification, err := entry.Notification()
+		if err != nil {
+			return err
+		}
+		if err := handler(ctx, notification); err != nil {
+			return fmt.Errorf("replay of %s failed: %w", entry.Key, err)
+		}
+		count++
+		return nil
+	})
+
+	return count, err
+}
+
+// ReplayToURL returns a replay handler posting the notifications to an HTTP endpoint.
+// Responses other than 2xx are returned as errors.
+//
+// Parameters:
+//   - url: The endpoint the notifications are posted to.
+//   - headers: Additional request headers (may be nil).
+//   - timeout: The request timeout.
+//
+// Returns:
+//   - The replay handler.
+func ReplayToURL(url string, headers map[string]string, timeout time.Duration) func(ctx context.Context, notification *WebhookNotification) error {
+	client := &http.Client{Timeout: timeout}
+
+	return func(ctx context.Context, notification *WebhookNotification) error {
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(notification.Raw))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Ctd-Hook-Type", notification.HookType)
+		req.Header.Set("X-Ctd-Event-Key", notification.Key())
+		req.Header.Set("X-Ctd-Replay", "1")
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("target responded with status %d", resp.StatusCode)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,67 @@
+package ctd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileJournal(t *testing.T) {
+	ctx := context.Background()
+
+	journal, err := NewFileJournal(t.TempDir())
+	require.NoError(t, err, "NewFileJournal() error")
+	defer journal.Close()
+
+	day := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	for i, hook := range []string{"inbox", "outbox", "inbox", "new_request"} {
+		notification, err := ParseWebhookNotification(fmt.Appendf(nil, `{"hook_type":%q,"message_id":%d}`, hook, i+1))
+		require.NoError(t, err, "ParseWebhookNotification() error")
+		require.NoError(t, journal.Append(ctx, NewJournalEntry(notification, day.Add(time.Duration(i)*12*time.Hour))), "journal.Append() error")
+	}
+
+	keys := func(filter JournalFilter) []string {
+		result := []string{}
+		require.NoError(t, journal.Read(ctx, filter, func(entry JournalEntry) error {
+			result = append(result, entry.Key)
+			return nil
+		}), "journal.Read() error")
+		return result
+	}
+
+	require.Equal(t, []string{"inbox:1", "outbox:2", "inbox:3", "new_request:4"}, keys(JournalFilter{}), "all entries")
+	require.Equal(t, []string{"outbox:2", "inbox:3"}, keys(JournalFilter{From: day.Add(12 * time.Hour), To: day.Add(36 * time.Hour)}), "time range")
+	require.Equal(t, []string{"inbox:1", "inbox:3"}, keys(JournalFilter{HookTypes: []string{"inbox"}}), "hook types")
+
+	t.Run("Replay", func(t *testing.T) {
+		received := []string{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			received = append(received, string(body))
+		}))
+		defer server.Close()
+
+		count, err := ReplayJournal(ctx, journal, JournalFilter{HookTypes: []string{"inbox"}}, ReplayToURL(server.URL, nil, time.Second))
+		require.NoError(t, err, "ReplayJournal() error")
+		require.Equal(t, 2, count, "ReplayJournal() should replay selected entries")
+		require.Equal(t, []string{`{"hook_type":"inbox","message_id":1}`, `{"hook_type":"inbox","message_id":3}`}, received, "original bodies should be sent")
+	})
+
+	t.Run("Journaled", func(t *testing.T) {
+		handled := 0
+		handler := Journaled(journal, func(ctx context.Context, notification *WebhookNotification) error {
+			handled++
+			return nil
+		})
+		notification, _ := ParseWebhookNotification([]byte(`{"hook_type":"close_dialog","message_id":5}`))
+		require.NoError(t, handler(ctx, notification), "journaled handler error")
+		require.Equal(t, 1, handled, "notification should be passed to the handler")
+		require.Equal(t, []string{"close_dialog:5"}, keys(JournalFilter{HookTypes: []string{"close_dialog"}}), "notification should be recorded")
+	})
+}
@@ -51,7 +51,7 @@ func (dst *Ctd) Login(ctx context.Context, login, password, personal_login, pers
 		return "", err
 	}
 
-	logging.Logs.Debugf(ctx, "Response: %s", string(result))
+	logging.Logs.Debugf(ctx, "Response: %s", dst.redact(string(result)))
 
 	response, err := dst.newLoginAPIParsing(ctx, result)
 	if err == nil {
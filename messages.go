@@ -59,6 +59,7 @@ type MessageAttachment struct {
 
 type Message struct {
 	ID              int64               `json:"id"`               // ID: Unique message ID
+	Text            string              `json:"text"`             // Text: Message text
 	Coordinates     string              `json:"coordinates"`      // Coordinates: Message coordinates (if any)
 	Transport       string              `json:"transport"`        // Transport: Transport
 	Type            string              `json:"type"`             // Type: Message type ('to_client', 'autoreply', 'system', 'comment')
@@ -83,6 +84,7 @@ type Message struct {
 
 // APISendMessage sends a message via the API.
 // It takes a context and a MessagePayload, and returns a MessageResponse or an error.
+// Unless Ctd.SkipValidation is set, the message is checked with ValidateMessage before sending.
 //
 // Parameters:
 //   - ctx (context.Context): The context for the request.
@@ -90,6 +92,7 @@ type Message struct {
 //
 // Returns:
 //   - A pointer to a MessageResponse containing the response data.
+//   - ErrorUnsupportedByTransport if the transport doesn't support the message.
 //   - An error if the request fails.
 func (dst *Ctd) APISendMessage(ctx context.Context, message *MessagePayload) (*SendMessageResponse, error) {
 	url := fmt.Sprintf("%sv1/messages", dst.Url)
@@ -100,6 +103,13 @@ func (dst *Ctd) APISendMessage(ctx context.Context, message *MessagePayload) (*S
 		message.Type = "to_client"
 	}
 
+	if !dst.SkipValidation {
+		if err := dst.ValidateMessage(message); err != nil {
+			dst.Error(ctx, "Failed send message: %v", err)
+			return nil, err
+		}
+	}
+
 	if _, err := dst.doRequest(ctx, "POST", url, message, &response); err != nil {
 		dst.Error(ctx, "Failed send message: %v", err)
 		return nil, err
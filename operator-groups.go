@@ -3,6 +3,8 @@ package ctd
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 )
 
 type OperatorGroup struct {
@@ -16,6 +18,11 @@ type OperatorGroupsResponse struct {
 	Data []OperatorGroup `json:"data"`
 }
 
+type OperatorGroupResponse struct {
+	BasicResponse
+	Data OperatorGroup `json:"data"`
+}
+
 // APIOperatorGroups retrieves a list of operator groups from the Chat2Desk API.
 // It constructs the API endpoint URL, sends a GET request to the API,
 // and returns the response data as an OperatorGroupsResponse struct.
@@ -63,3 +70,386 @@ func (dst *Ctd) OperatorGroups(ctx context.Context) ([]OperatorGroup, error) {
 
 	return data.Data, nil
 }
+
+// APICreateOperatorGroup creates a new operator group in the Chat2Desk API.
+// It constructs the API endpoint URL, sends a POST request with the group name and operators,
+// and returns the response data as an OperatorGroupResponse struct.
+// If an error occurs during the request, it logs the error and returns it.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - name: The name of the new group.
+//   - operator_ids: The IDs of the operators to add to the group (optional).
+//
+// Returns:
+//   - A pointer to an OperatorGroupResponse struct containing the created group.
+//   - An error if the request fails or if the response is invalid.
+func (dst *Ctd) APICreateOperatorGroup(ctx context.Context, name string, operator_ids []int64) (*OperatorGroupResponse, error) {
+	url := fmt.Sprintf("%sv1/operators_groups", dst.Url)
+	payload := map[string]any{
+		"name": name,
+	}
+	if len(operator_ids) > 0 {
+		payload["operator_ids"] = operator_ids
+	}
+	response := OperatorGroupResponse{}
+
+	if _, err := dst.doRequest(ctx, "POST", url, payload, &response); err != nil {
+		dst.Error(ctx, "Failed to create operator group: %v", err)
+		return nil, err
+	}
+	return &response, nil
+}
+
+// APIUpdateOperatorGroup renames an operator group in the Chat2Desk API.
+// It constructs the API endpoint URL with the group ID, sends a PUT request with the new name,
+// and returns the response data as an OperatorGroupResponse struct.
+// If an error occurs during the request, it logs the error and returns it.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the group.
+//   - name: The new name of the group.
+//
+// Returns:
+//   - A pointer to an OperatorGroupResponse struct containing the updated group.
+//   - An error if the request fails or if the response is invalid.
+func (dst *Ctd) APIUpdateOperatorGroup(ctx context.Context, id int64, name string) (*OperatorGroupResponse, error) {
+	url := fmt.Sprintf("%sv1/operators_groups/%d", dst.Url, id)
+	payload := map[string]any{
+		"name": name,
+	}
+	response := OperatorGroupResponse{}
+
+	if _, err := dst.doRequest(ctx, "PUT", url, payload, &response); err != nil {
+		dst.Error(ctx, "Failed to update operator group: %v", err)
+		return nil, err
+	}
+	return &response, nil
+}
+
+// APIDeleteOperatorGroup deletes an operator group in the Chat2Desk API.
+// It constructs the API endpoint URL with the group ID, sends a DELETE request,
+// and returns the response data as a BasicResponse struct.
+// If an error occurs during the request, it logs the error and returns it.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the group to delete.
+//
+// Returns:
+//   - A pointer to a BasicResponse struct containing the response data.
+//   - An error if the request fails or if the response is invalid.
+func (dst *Ctd) APIDeleteOperatorGroup(ctx context.Context, id int64) (*BasicResponse, error) {
+	url := fmt.Sprintf("%sv1/operators_groups/%d", dst.Url, id)
+	response := BasicResponse{}
+
+	if _, err := dst.doRequest(ctx, "DELETE", url, nil, &response); err != nil {
+		dst.Error(ctx, "Failed to delete operator group: %v", err)
+		return nil, err
+	}
+	return &response, nil
+}
+
+// APIOperatorGroupMembers adds operators to or removes operators from an operator group in the Chat2Desk API.
+// It constructs the API endpoint URL with the group ID and sends a POST (add) or DELETE (remove) request
+// with the operator IDs, and returns the response data as a BasicResponse struct.
+// If an error occurs during the request, it logs the error and returns it.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the group.
+//   - operator_ids: The IDs of the operators to add or remove.
+//   - mode: The operation mode ('add' or 'remove').
+//
+// Returns:
+//   - A pointer to a BasicResponse struct containing the response data.
+//   - An error if the request fails or if the response is invalid.
+func (dst *Ctd) APIOperatorGroupMembers(ctx context.Context, id int64, operator_ids []int64, mode string) (*BasicResponse, error) {
+	if len(operator_ids) == 0 {
+		return nil, ErrorInvalidParameters
+	}
+
+	url := fmt.Sprintf("%sv1/operators_groups/%d/operators", dst.Url, id)
+	method := "POST"
+	if mode == "remove" {
+		method = "DELETE"
+	}
+	payload := map[string]any{
+		"operator_ids": operator_ids,
+	}
+	response := BasicResponse{}
+
+	if _, err := dst.doRequest(ctx, method, url, payload, &response); err != nil {
+		dst.Error(ctx, "Failed to change operator group members: %v", err)
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetOperatorGroup retrieves an operator group by its ID.
+// It uses the OperatorGroups method to fetch the groups and looks up the requested one.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the group.
+//
+// Returns:
+//   - A pointer to an OperatorGroup containing the group.
+//   - An error if the request fails or if the group is not found (ErrorInvalidOperatorGroupID).
+func (dst *Ctd) GetOperatorGroup(ctx context.Context, id int64) (*OperatorGroup, error) {
+	groups, err := dst.OperatorGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		if group.ID == id {
+			return &group, nil
+		}
+	}
+
+	return nil, ErrorInvalidOperatorGroupID
+}
+
+// CreateOperatorGroup creates a new operator group.
+// It uses the APICreateOperatorGroup method to create the group and handles errors.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - name: The name of the new group.
+//   - operator_ids: The IDs of the operators to add to the group (optional).
+//
+// Returns:
+//   - A pointer to an OperatorGroup containing the created group.
+//   - An error if the request fails or if the response is invalid.
+func (dst *Ctd) CreateOperatorGroup(ctx context.Context, name string, operator_ids []int64) (*OperatorGroup, error) {
+	if name == "" {
+		return nil, ErrorInvalidParameters
+	}
+
+	data, err := dst.APICreateOperatorGroup(ctx, name, operator_ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Status != "success" {
+		dst.Error(ctx, "Failed to create operator group: %s", data.Errors)
+		return nil, operatorGroupError(&data.BasicResponse)
+	}
+
+	return &data.Data, nil
+}
+
+// RenameOperatorGroup renames an operator group.
+// It uses the APIUpdateOperatorGroup method to rename the group and handles errors.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the group.
+//   - name: The new name of the group.
+//
+// Returns:
+//   - A pointer to an OperatorGroup containing the updated group.
+//   - An error if the request fails, if the group is not found or if the response is invalid.
+func (dst *Ctd) RenameOperatorGroup(ctx context.Context, id int64, name string) (*OperatorGroup, error) {
+	if name == "" {
+		return nil, ErrorInvalidParameters
+	}
+
+	data, err := dst.APIUpdateOperatorGroup(ctx, id, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Status != "success" {
+		dst.Error(ctx, "Failed to rename operator group: %s", data.Errors)
+		return nil, operatorGroupError(&data.BasicResponse)
+	}
+
+	return &data.Data, nil
+}
+
+// DeleteOperatorGroup deletes an operator group.
+// It uses the APIDeleteOperatorGroup method to delete the group and handles errors.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the group to delete.
+//
+// Returns:
+//   - An error if the request fails, if the group is not found or if the response is invalid.
+func (dst *Ctd) DeleteOperatorGroup(ctx context.Context, id int64) error {
+	data, err := dst.APIDeleteOperatorGroup(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if data.Status != "success" {
+		dst.Error(ctx, "Failed to delete operator group: %s", data.Errors)
+		return operatorGroupError(data)
+	}
+
+	return nil
+}
+
+// AddOperatorsToGroup adds operators to an operator group.
+// It uses the APIOperatorGroupMembers method to add the operators and handles errors.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the group.
+//   - operator_ids: The IDs of the operators to add.
+//
+// Returns:
+//   - An error if the request fails, if the group or an operator is not found or if the response is invalid.
+func (dst *Ctd) AddOperatorsToGroup(ctx context.Context, id int64, operator_ids []int64) error {
+	data, err := dst.APIOperatorGroupMembers(ctx, id, operator_ids, "add")
+	if err != nil {
+		return err
+	}
+
+	if data.Status != "success" {
+		dst.Error(ctx, "Failed to add operators to group: %s", data.Errors)
+		return operatorGroupError(data)
+	}
+
+	return nil
+}
+
+// RemoveOperatorsFromGroup removes operators from an operator group.
+// It uses the APIOperatorGroupMembers method to remove the operators and handles errors.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the group.
+//   - operator_ids: The IDs of the operators to remove.
+//
+// Returns:
+//   - An error if the request fails, if the group or an operator is not found or if the response is invalid.
+func (dst *Ctd) RemoveOperatorsFromGroup(ctx context.Context, id int64, operator_ids []int64) error {
+	data, err := dst.APIOperatorGroupMembers(ctx, id, operator_ids, "remove")
+	if err != nil {
+		return err
+	}
+
+	if data.Status != "success" {
+		dst.Error(ctx, "Failed to remove operators from group: %s", data.Errors)
+		return operatorGroupError(data)
+	}
+
+	return nil
+}
+
+// SyncOperatorGroup makes the membership of an operator group match the desired list of operators.
+// It fetches the group, computes the operators to add and to remove and applies the changes.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the group.
+//   - operator_ids: The desired IDs of the group operators.
+//
+// Returns:
+//   - The IDs of the added operators.
+//   - The IDs of the removed operators.
+//   - An error if the request fails, if the group is not found or if the response is invalid.
+func (dst *Ctd) SyncOperatorGroup(ctx context.Context, id int64, operator_ids []int64) ([]int64, []int64, error) {
+	group, err := dst.GetOperatorGroup(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	added, removed := diffIDs(group.Operators, operator_ids)
+
+	if len(added) > 0 {
+		if err := dst.AddOperatorsToGroup(ctx, id, added); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(removed) > 0 {
+		if err := dst.RemoveOperatorsFromGroup(ctx, id, removed); err != nil {
+			return added, nil, err
+		}
+	}
+
+	return added, removed, nil
+}
+
+// OperatorGroupsMembers returns the operators of every operator group resolved to full Operator records.
+// Operators that are listed in a group but are not returned by AllOperators are skipped.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//
+// Returns:
+//   - A map of group ID to the operators of the group.
+//   - An error if the request fails or if the response is invalid.
+func (dst *Ctd) OperatorGroupsMembers(ctx context.Context) (map[int64][]Operator, error) {
+	groups, err := dst.OperatorGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	operators, err := dst.AllOperators(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]Operator, len(operators))
+	for _, operator := range operators {
+		byID[operator.ID] = operator
+	}
+
+	result := make(map[int64][]Operator, len(groups))
+	for _, group := range groups {
+		members := []Operator{}
+		for _, id := range group.Operators {
+			if operator, ok := byID[id]; ok {
+				members = append(members, operator)
+			}
+		}
+		result[group.ID] = members
+	}
+
+	return result, nil
+}
+
+// operatorGroupError converts an unsuccessful operator group response into an error.
+func operatorGroupError(response *BasicResponse) error {
+	str := strings.ToLower(fmt.Sprintf("%s %v", response.Message, response.Errors))
+	if strings.Contains(str, "not found") || strings.Contains(str, "not_found") {
+		if strings.Contains(str, "group") {
+			return ErrorInvalidOperatorGroupID
+		}
+		if strings.Contains(str, "operator") {
+			return ErrorInvalidOperatorID
+		}
+		return ErrorInvalidOperatorGroupID
+	}
+
+	return ErrorInvalidParameters
+}
+
+// diffIDs returns the IDs that are missing from current (to add) and
+// the IDs that are not in desired (to remove). Both results are sorted.
+func diffIDs[T int | int64](current, desired []T) ([]T, []T) {
+	added := []T{}
+	for _, id := range desired {
+		if !slices.Contains(current, id) && !slices.Contains(added, id) {
+			added = append(added, id)
+		}
+	}
+
+	removed := []T{}
+	for _, id := range current {
+		if !slices.Contains(desired, id) && !slices.Contains(removed, id) {
+			removed = append(removed, id)
+		}
+	}
+
+	slices.Sort(added)
+	slices.Sort(removed)
+
+	return added, removed
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/ra-company/env"
 	"github.com/stretchr/testify/require"
 )
 
@@ -61,3 +63,65 @@ func TestCtdApi_OperatorGroups(t *testing.T) {
 		})
 	}
 }
+
+func TestCtdApi_OperatorGroupsCRUD(t *testing.T) {
+	ctx := context.Background()
+	faker := gofakeit.New(0)
+
+	url, token := getCredentials(t)
+
+	operatorID := int64(env.GetEnvInt("API_OPERATOR_ID", 0))
+	require.NotEqual(t, int64(0), operatorID, "API_OPERATOR_ID must be set in .env file and .settings")
+
+	dst := &Ctd{}
+	dst.Init(url, token)
+
+	var groupID int64
+	t.Run("01 CreateOperatorGroup", func(t *testing.T) {
+		got, err := dst.CreateOperatorGroup(ctx, faker.Company(), nil)
+		require.NoError(t, err, "dst.CreateOperatorGroup() error")
+		require.NotNil(t, got, "dst.CreateOperatorGroup() should return data")
+		groupID = got.ID
+	})
+
+	t.Run("02 RenameOperatorGroup", func(t *testing.T) {
+		name := faker.Company()
+		got, err := dst.RenameOperatorGroup(ctx, groupID, name)
+		require.NoError(t, err, "dst.RenameOperatorGroup() error")
+		require.Equal(t, name, got.Name, "dst.RenameOperatorGroup() should return new name")
+	})
+
+	t.Run("03 SyncOperatorGroup", func(t *testing.T) {
+		added, removed, err := dst.SyncOperatorGroup(ctx, groupID, []int64{operatorID})
+		require.NoError(t, err, "dst.SyncOperatorGroup() error")
+		require.Equal(t, []int64{operatorID}, added, "dst.SyncOperatorGroup() added operators")
+		require.Empty(t, removed, "dst.SyncOperatorGroup() removed operators")
+
+		members, err := dst.OperatorGroupsMembers(ctx)
+		require.NoError(t, err, "dst.OperatorGroupsMembers() error")
+		require.Len(t, members[groupID], 1, "dst.OperatorGroupsMembers() should return group operators")
+
+		added, removed, err = dst.SyncOperatorGroup(ctx, groupID, []int64{})
+		require.NoError(t, err, "dst.SyncOperatorGroup() error")
+		require.Empty(t, added, "dst.SyncOperatorGroup() added operators")
+		require.Equal(t, []int64{operatorID}, removed, "dst.SyncOperatorGroup() removed operators")
+	})
+
+	t.Run("04 DeleteOperatorGroup", func(t *testing.T) {
+		err := dst.DeleteOperatorGroup(ctx, groupID)
+		require.NoError(t, err, "dst.DeleteOperatorGroup() error")
+
+		_, err = dst.GetOperatorGroup(ctx, groupID)
+		require.ErrorIs(t, err, ErrorInvalidOperatorGroupID, "dst.GetOperatorGroup() error")
+	})
+}
+
+func TestDiffIDs(t *testing.T) {
+	added, removed := diffIDs([]int64{1, 2, 3}, []int64{5, 3, 4, 4})
+	require.Equal(t, []int64{4, 5}, added, "diffIDs() added")
+	require.Equal(t, []int64{1, 2}, removed, "diffIDs() removed")
+
+	added, removed = diffIDs([]int64{}, []int64{})
+	require.Empty(t, added, "diffIDs() added")
+	require.Empty(t, removed, "diffIDs() removed")
+}
@@ -3,6 +3,7 @@ package ctd
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -21,38 +22,10 @@ type Operator struct {
 	AccessRightID   int64  `json:"access_right_id"`
 }
 
+// GetLegacyRole returns the role of the operator as a string ('operator', 'supervisor', 'admin', 'disabled', 'deleted'
+// or a custom role). The role is resolved by the DefaultRoleResolver.
 func (dst *Operator) GetLegacyRole() string {
-	// Check new API version first
-	if dst.Status != "" {
-		str := strings.ToLower(dst.Status)
-		switch str {
-		case "admin", "disabled", "deleted":
-			return str
-		}
-		str = strings.ToLower(dst.AccessRightName)
-		roles := []string{"Супервайзер", "Supervisor", "Amir", "O supervisor", "El supervisor", "Supervayzer", "Supervizor", "Le superviseur"}
-		for _, role := range roles {
-			role := strings.ToLower(role)
-			if str == role || strings.Contains(str, fmt.Sprintf("(%s)", role)) {
-				return "supervisor"
-			}
-		}
-		roles = []string{"Администратор", "Administrator", "Admin", "O administrador", "El administrador", "Administrateur", "Адміністратор"}
-		for _, role := range roles {
-			role := strings.ToLower(role)
-			if str == role || strings.Contains(str, fmt.Sprintf("(%s)", role)) {
-				return "admin"
-			}
-		}
-		return "operator"
-	}
-
-	// Fallback to old API version
-	if dst.Role != "" {
-		return strings.ToLower(dst.Role)
-	}
-
-	return "operator"
+	return string(dst.GetRole())
 }
 
 type OperatorsResponse struct {
@@ -61,11 +34,46 @@ type OperatorsResponse struct {
 	BasicResponse
 }
 
+type OperatorResponse struct {
+	Data Operator `json:"data"`
+	BasicResponse
+}
+
 type OperatorStatus struct {
 	ID   int64             `json:"id"`
 	Name map[string]string `json:"name"`
 }
 
+// GetName returns the localized name of the operator status.
+// If there is no name for the requested language, it falls back to English, then Russian,
+// and then to any available name.
+//
+// Parameters:
+//   - lang: The language code (e.g. "en", "ru").
+//
+// Returns:
+//   - The localized status name or an empty string if the status has no names.
+func (dst *OperatorStatus) GetName(lang string) string {
+	for _, key := range []string{strings.ToLower(lang), "en", "ru"} {
+		if name, ok := dst.Name[key]; ok && name != "" {
+			return name
+		}
+	}
+
+	keys := make([]string, 0, len(dst.Name))
+	for key := range dst.Name {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if dst.Name[key] != "" {
+			return dst.Name[key]
+		}
+	}
+
+	return ""
+}
+
 // APIOperators retrieves a list of operators from the Chat2Desk API.
 // It constructs the API endpoint URL with the provided offset and limit,
 // sends a GET request to the API, and returns the response data as a OperatorsResponse struct.
@@ -118,6 +126,67 @@ func (dst *Ctd) APIOperatorStatuses(ctx context.Context) ([]OperatorStatus, erro
 	return response, nil
 }
 
+// APIGetOperator retrieves an operator by its ID from the Chat2Desk API.
+// It constructs the API endpoint URL with the provided operator ID,
+// sends a GET request to the API, and returns the response data as an OperatorResponse struct.
+// If an error occurs during the request, it logs the error and returns it.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the operator to retrieve.
+//
+// Returns:
+//   - A pointer to an OperatorResponse struct containing the operator.
+//   - An error if the request fails or if the response is invalid.
+func (dst *Ctd) APIGetOperator(ctx context.Context, id int64) (*OperatorResponse, error) {
+	url := fmt.Sprintf("%sv1/operators/%d", dst.Url, id)
+
+	response := OperatorResponse{}
+
+	body, err := dst.doRequest(ctx, "GET", url, nil, &response)
+	if err == ErrorInvalidResponse {
+		if strings.Contains(strings.ToLower(string(body)), "not found") {
+			return nil, ErrorInvalidOperatorID
+		}
+	}
+
+	if err != nil {
+		dst.Error(ctx, "Failed to get operator: %v", err)
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// APISetOperatorStatus changes the status of an operator in the Chat2Desk API.
+// It constructs the API endpoint URL with the provided operator ID,
+// sends a PUT request with the status ID, and returns the response data as a BasicResponse struct.
+// If an error occurs during the request, it logs the error and returns it.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the operator.
+//   - status_id: The ID of the new status (see APIOperatorStatuses).
+//
+// Returns:
+//   - A pointer to a BasicResponse struct containing the response data.
+//   - An error if the request fails or if the response is invalid.
+func (dst *Ctd) APISetOperatorStatus(ctx context.Context, id, status_id int64) (*BasicResponse, error) {
+	url := fmt.Sprintf("%sv1/operators/%d/status", dst.Url, id)
+	payload := map[string]int64{
+		"status_id": status_id,
+	}
+
+	response := BasicResponse{}
+
+	if _, err := dst.doRequest(ctx, "PUT", url, payload, &response); err != nil {
+		dst.Error(ctx, "Failed to set operator status: %v", err)
+		return nil, err
+	}
+
+	return &response, nil
+}
+
 // Operators retrieves a list of operators from the Chat2Desk API.
 // It uses the APIOperators method to fetch the operators and handles errors.
 // If the response status is not "success", it returns nil.
@@ -176,3 +245,58 @@ func (dst *Ctd) AllOperators(ctx context.Context) ([]Operator, error) {
 
 	return operators, nil
 }
+
+// GetOperator retrieves an operator by its ID from the Chat2Desk API.
+// It uses the APIGetOperator method to fetch the operator and handles errors.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the operator to retrieve.
+//
+// Returns:
+//   - A pointer to an Operator containing the operator data.
+//   - An error if the request fails, if the operator is not found or if the response is invalid.
+func (dst *Ctd) GetOperator(ctx context.Context, id int64) (*Operator, error) {
+	data, err := dst.APIGetOperator(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Status != "success" {
+		dst.Error(ctx, "Failed to get operator: %s", data.Errors)
+		if data.Message == "not_found" || strings.Contains(strings.ToLower(fmt.Sprintf("%v", data.Errors)), "not found") {
+			return nil, ErrorInvalidOperatorID
+		}
+		return nil, ErrorInvalidParameters
+	}
+
+	return &data.Data, nil
+}
+
+// SetOperatorStatus changes the status of an operator.
+// It uses the APISetOperatorStatus method to change the status and handles errors.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the operator.
+//   - status_id: The ID of the new status (see APIOperatorStatuses).
+//
+// Returns:
+//   - An error if the request fails, if the operator is not found or if the response is invalid.
+func (dst *Ctd) SetOperatorStatus(ctx context.Context, id, status_id int64) error {
+	data, err := dst.APISetOperatorStatus(ctx, id, status_id)
+	if err != nil {
+		return err
+	}
+
+	if data.Status != "success" {
+		dst.Error(ctx, "Failed to set operator status: %s", data.Errors)
+		str := strings.ToLower(fmt.Sprintf("%v %v", data.Message, data.Errors))
+		if strings.Contains(str, "operator") && strings.Contains(str, "not found") {
+			return ErrorInvalidOperatorID
+		}
+		return ErrorInvalidParameters
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/ra-company/env"
 	"github.com/stretchr/testify/require"
 )
 
@@ -156,3 +157,89 @@ func TestOperator_GetLegacyRole(t *testing.T) {
 		})
 	}
 }
+
+func TestCtdApi_GetOperator(t *testing.T) {
+	ctx := context.Background()
+
+	url, token := getCredentials(t)
+
+	operatorID := int64(env.GetEnvInt("API_OPERATOR_ID", 0))
+	require.NotEqual(t, int64(0), operatorID, "API_OPERATOR_ID must be set in .env file and .settings")
+
+	t.Run("Incorrect token", func(t *testing.T) {
+		dst := &Ctd{}
+		dst.Init(url, "incorrect_token")
+		got, err := dst.GetOperator(ctx, operatorID)
+		require.ErrorIs(t, err, ErrorInvalidToken, "dst.GetOperator() error")
+		require.Nil(t, got, "dst.GetOperator() should return nil data on error")
+	})
+
+	t.Run("Incorrect ID", func(t *testing.T) {
+		dst := &Ctd{}
+		dst.Init(url, token)
+		got, err := dst.GetOperator(ctx, 1)
+		require.ErrorIs(t, err, ErrorInvalidOperatorID, "dst.GetOperator() error")
+		require.Nil(t, got, "dst.GetOperator() should return nil data on error")
+	})
+
+	t.Run("Correct ID", func(t *testing.T) {
+		dst := &Ctd{}
+		dst.Init(url, token)
+		got, err := dst.GetOperator(ctx, operatorID)
+		require.NoError(t, err, "dst.GetOperator() error")
+		require.NotNil(t, got, "dst.GetOperator() should return data")
+		require.Equal(t, operatorID, got.ID, "dst.GetOperator() should return correct operator ID")
+	})
+
+	t.Run("SetOperatorStatus", func(t *testing.T) {
+		dst := &Ctd{}
+		dst.Init(url, token)
+		got, err := dst.GetOperator(ctx, operatorID)
+		require.NoError(t, err, "dst.GetOperator() error")
+
+		err = dst.SetOperatorStatus(ctx, operatorID, int64(got.StatusID))
+		require.NoError(t, err, "dst.SetOperatorStatus() error")
+
+		err = dst.SetOperatorStatus(ctx, 1, int64(got.StatusID))
+		require.ErrorIs(t, err, ErrorInvalidOperatorID, "dst.SetOperatorStatus() error")
+	})
+}
+
+func TestOperatorStatus_GetName(t *testing.T) {
+	tests := []struct {
+		name   string
+		status OperatorStatus
+		lang   string
+		want   string
+	}{
+		{
+			name:   "Requested language",
+			status: OperatorStatus{Name: map[string]string{"en": "Busy", "ru": "Занят"}},
+			lang:   "RU",
+			want:   "Занят",
+		},
+		{
+			name:   "Fallback to English",
+			status: OperatorStatus{Name: map[string]string{"en": "Busy", "ru": "Занят"}},
+			lang:   "pt",
+			want:   "Busy",
+		},
+		{
+			name:   "Fallback to any language",
+			status: OperatorStatus{Name: map[string]string{"pt": "Ocupado"}},
+			lang:   "es",
+			want:   "Ocupado",
+		},
+		{
+			name:   "No names",
+			status: OperatorStatus{},
+			lang:   "en",
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.status.GetName(tt.lang), "OperatorStatus.GetName()")
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package ctd
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	PresenceOnline        = "online"         // Operator went online
+	PresenceOffline       = "offline"        // Operator went offline
+	PresenceStatusChanged = "status_changed" // Operator changed status while staying online
+)
+
+// PresenceEvent describes a change of an operator's presence detected by the PresenceTracker.
+type PresenceEvent struct {
+	Type     string    // Type: Event type ('online', 'offline', 'status_changed')
+	Operator Operator  // Operator: Current state of the operator
+	Previous Operator  // Previous: Previous state of the operator
+	Time     time.Time // Time: Time when the change was detected
+}
+
+// PresenceTracker polls AllOperators and reports operators going online, offline
+// or changing their status. Events are delivered to the OnEvent callback and,
+// if Events was called before Run, to the events channel. A tracker is run once:
+// after Run returns, Events returns a closed channel.
+// The first poll only records the initial state and doesn't produce events.
+type PresenceTracker struct {
+	Interval time.Duration                                  // Interval: Polling interval (default: 30 seconds)
+	OnEvent  func(ctx context.Context, event PresenceEvent) // OnEvent: Optional callback called for every event
+
+	ctd       *Ctd
+	mu        sync.RWMutex
+	operators map[int64]Operator
+	events    chan PresenceEvent
+	stopped   bool
+}
+
+// NewPresenceTracker creates a new PresenceTracker with the specified polling interval.
+//
+// Parameters:
+//   - interval: The polling interval. If zero, 30 seconds is used.
+//
+// Returns:
+//   - A pointer to a PresenceTracker.
+func (dst *Ctd) NewPresenceTracker(interval time.Duration) *PresenceTracker {
+	return &PresenceTracker{
+		Interval: interval,
+		ctd:      dst,
+	}
+}
+
+// Events returns the channel the events are delivered to.
+// The channel is closed when Run returns. If the channel is requested,
+// it must be read, otherwise polling blocks until the context is canceled.
+func (dst *PresenceTracker) Events() <-chan PresenceEvent {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	if dst.events == nil {
+		dst.events = make(chan PresenceEvent, 100)
+		if dst.stopped {
+			close(dst.events)
+		}
+	}
+	return dst.events
+}
+
+// Operators returns the operators known after the last poll sorted by ID.
+func (dst *PresenceTracker) Operators() []Operator {
+	dst.mu.RLock()
+	defer dst.mu.RUnlock()
+
+	operators := make([]Operator, 0, len(dst.operators))
+	for _, operator := range dst.operators {
+		operators = append(operators, operator)
+	}
+	sort.Slice(operators, func(i, j int) bool {
+		return operators[i].ID < operators[j].ID
+	})
+
+	return operators
+}
+
+// Operator returns the operator known after the last poll.
+//
+// Parameters:
+//   - id: The ID of the operator.
+//
+// Returns:
+//   - The operator and true if the operator is known, or false otherwise.
+func (dst *PresenceTracker) Operator(id int64) (Operator, bool) {
+	dst.mu.RLock()
+	defer dst.mu.RUnlock()
+
+	operator, ok := dst.operators[id]
+	return operator, ok
+}
+
+// Poll fetches the operators once, updates the known state and delivers the detected events.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//
+// Returns:
+//   - A slice of PresenceEvent detected by this poll.
+//   - An error if the request fails or if the response is invalid.
+func (dst *PresenceTracker) Poll(ctx context.Context) ([]PresenceEvent, error) {
+	operators, err := dst.ctd.AllOperators(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[int64]Operator, len(operators))
+	for _, operator := range operators {
+		current[operator.ID] = operator
+	}
+
+	dst.mu.Lock()
+	previous := dst.operators
+	dst.operators = current
+	events := dst.events
+	if dst.stopped {
+		// The channel is closed by Run
+		events = nil
+	}
+	dst.mu.Unlock()
+
+	if previous == nil {
+		return nil, nil
+	}
+
+	result := presenceEvents(previous, current, time.Now())
+	for _, event := range result {
+		if dst.OnEvent != nil {
+			dst.OnEvent(ctx, event)
+		}
+		if events != nil {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return result, ctx.Err()
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Run polls the operators with the configured interval until the context is canceled.
+// Polling errors are logged and polling continues.
+//
+// Parameters:
+//   - ctx: The context controlling the tracker lifetime.
+//
+// Returns:
+//   - The context error when the context is canceled.
+func (dst *PresenceTracker) Run(ctx context.Context) error {
+	defer func() {
+		dst.mu.Lock()
+		dst.stopped = true
+		if dst.events != nil {
+			close(dst.events)
+		}
+		dst.mu.Unlock()
+	}()
+
+	interval := dst.Interval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := dst.Poll(ctx); err != nil && ctx.Err() == nil {
+			dst.ctd.Error(ctx, "Failed to poll operators presence: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// presenceEvents compares two snapshots of operators and returns the presence changes sorted by operator ID.
+// Operators missing from the current snapshot are reported as offline.
+func presenceEvents(previous, current map[int64]Operator, now time.Time) []PresenceEvent {
+	events := []PresenceEvent{}
+
+	for id, operator := range current {
+		before, ok := previous[id]
+		if !ok {
+			if operator.Online == 1 {
+				events = append(events, PresenceEvent{Type: PresenceOnline, Operator: operator, Time: now})
+			}
+			continue
+		}
+
+		switch {
+		case before.Online != 1 && operator.Online == 1:
+			events = append(events, PresenceEvent{Type: PresenceOnline, Operator: operator, Previous: before, Time: now})
+		case before.Online == 1 && operator.Online != 1:
+			events = append(events, PresenceEvent{Type: PresenceOffline, Operator: operator, Previous: before, Time: now})
+		case operator.Online == 1 && before.StatusID != operator.StatusID:
+			events = append(events, PresenceEvent{Type: PresenceStatusChanged, Operator: operator, Previous: before, Time: now})
+		}
+	}
+
+	for id, before := range previous {
+		if _, ok := current[id]; !ok && before.Online == 1 {
+			offline := before
+			offline.Online = 0
+			events = append(events, PresenceEvent{Type: PresenceOffline, Operator: offline, Previous: before, Time: now})
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Operator.ID < events[j].Operator.ID
+	})
+
+	return events
+}
@@ -0,0 +1,75 @@
+package ctd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresenceEvents(t *testing.T) {
+	now := time.Now()
+	previous := map[int64]Operator{
+		1: {ID: 1, Online: 0},
+		2: {ID: 2, Online: 1, StatusID: 1},
+		3: {ID: 3, Online: 1, StatusID: 1},
+		4: {ID: 4, Online: 1, StatusID: 1},
+		5: {ID: 5, Online: 1, StatusID: 1},
+	}
+	current := map[int64]Operator{
+		1: {ID: 1, Online: 1},
+		2: {ID: 2, Online: 0, StatusID: 1},
+		3: {ID: 3, Online: 1, StatusID: 2},
+		4: {ID: 4, Online: 1, StatusID: 1},
+		6: {ID: 6, Online: 1},
+		7: {ID: 7, Online: 0},
+	}
+
+	events := presenceEvents(previous, current, now)
+	got := map[int64]string{}
+	for _, event := range events {
+		got[event.Operator.ID] = event.Type
+		require.Equal(t, now, event.Time, "event time")
+	}
+
+	require.Equal(t, map[int64]string{
+		1: PresenceOnline,
+		2: PresenceOffline,
+		3: PresenceStatusChanged,
+		5: PresenceOffline,
+		6: PresenceOnline,
+	}, got, "presenceEvents()")
+	require.Equal(t, uint8(1), events[2].Previous.StatusID, "previous state of the operator")
+}
+
+func TestPresenceTracker_Events(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"success","data":[{"id":1,"online":1}],"meta":{"total":1}}`))
+	}))
+	defer server.Close()
+
+	dst := &Ctd{}
+	dst.Init(server.URL, "token")
+
+	tracker := dst.NewPresenceTracker(time.Millisecond)
+	events := tracker.Events()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, tracker.Run(ctx), context.DeadlineExceeded, "tracker.Run() should return the context error")
+
+	_, ok := <-events
+	require.False(t, ok, "events channel should be closed when Run returns")
+	select {
+	case _, ok = <-tracker.Events():
+		require.False(t, ok, "tracker.Events() should return a closed channel after Run")
+	case <-time.After(time.Second):
+		require.Fail(t, "tracker.Events() blocks after Run")
+	}
+
+	_, err := tracker.Poll(context.Background())
+	require.NoError(t, err, "tracker.Poll() after Run")
+}
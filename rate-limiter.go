@@ -0,0 +1,67 @@
+package ctd
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rate of the API requests.
+// Requests are spread evenly: every request waits for its own slot.
+// A nil RateLimiter doesn't limit anything.
+type RateLimiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	next     time.Time
+}
+
+// NewRateLimiter creates a new RateLimiter allowing the specified number of requests per second.
+//
+// Parameters:
+//   - rps: The number of requests per second. If zero or negative, requests are not limited.
+//
+// Returns:
+//   - A pointer to a RateLimiter.
+func NewRateLimiter(rps float64) *RateLimiter {
+	limiter := &RateLimiter{}
+	if rps > 0 {
+		limiter.interval = time.Duration(float64(time.Second) / rps)
+	}
+	return limiter
+}
+
+// Wait blocks until the next request is allowed or the context is canceled.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//
+// Returns:
+//   - The context error if the context is canceled while waiting.
+func (dst *RateLimiter) Wait(ctx context.Context) error {
+	if dst == nil || dst.interval <= 0 {
+		return nil
+	}
+
+	dst.mu.Lock()
+	now := time.Now()
+	if dst.next.Before(now) {
+		dst.next = now
+	}
+	wait := dst.next.Sub(now)
+	dst.next = dst.next.Add(dst.interval)
+	dst.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
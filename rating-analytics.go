@@ -0,0 +1,287 @@
+package ctd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RatingScale describes the rating scale of the company and the thresholds of the metrics.
+type RatingScale struct {
+	Min          int64 // Min: Minimum valid rating
+	Max          int64 // Max: Maximum valid rating
+	SatisfiedMin int64 // SatisfiedMin: Minimum rating counted as satisfied by CSAT
+	PromoterMin  int64 // PromoterMin: Minimum rating of a promoter for NPS
+	DetractorMax int64 // DetractorMax: Maximum rating of a detractor for NPS
+}
+
+var (
+	RatingScaleFive = RatingScale{Min: 1, Max: 5, SatisfiedMin: 4, PromoterMin: 5, DetractorMax: 3}  // Five-star scale
+	RatingScaleTen  = RatingScale{Min: 0, Max: 10, SatisfiedMin: 8, PromoterMin: 9, DetractorMax: 6} // Classic NPS scale
+)
+
+// RatingSummary holds the metrics calculated from ratings.
+type RatingSummary struct {
+	Count      int           // Count: Number of valid ratings
+	Invalid    int           // Invalid: Number of ratings that are empty or out of the scale
+	Average    float64       // Average: Average rating
+	Histogram  map[int64]int // Histogram: Number of ratings by value
+	Satisfied  int           // Satisfied: Number of ratings of at least RatingScale.SatisfiedMin
+	CSAT       float64       // CSAT: Percentage of satisfied ratings (0-100)
+	Promoters  int           // Promoters: Number of ratings of at least RatingScale.PromoterMin
+	Passives   int           // Passives: Number of ratings between the detractors and the promoters
+	Detractors int           // Detractors: Number of ratings of at most RatingScale.DetractorMax
+	NPS        float64       // NPS: Percentage of promoters minus percentage of detractors (-100-100)
+}
+
+// OperatorRatingSummary holds the rating metrics of an operator.
+type OperatorRatingSummary struct {
+	OperatorID int64  // OperatorID: ID of the operator (0 - requests without an operator)
+	Name       string // Name: First and last name of the operator
+	RatingSummary
+}
+
+// SummarizeRatings calculates CSAT, NPS, the average and the histogram of the ratings.
+//
+// Parameters:
+//   - ratings: The ratings.
+//   - scale: The rating scale.
+//
+// Returns:
+//   - The rating metrics.
+func SummarizeRatings(ratings []StatisticsRating, scale RatingScale) RatingSummary {
+	result := RatingSummary{Histogram: map[int64]int{}}
+	sum := int64(0)
+
+	for _, rating := range ratings {
+		value := rating.GetScoreValue()
+		if value == -1 || value < scale.Min || value > scale.Max {
+			result.Invalid++
+			continue
+		}
+
+		result.Count++
+		sum += value
+		result.Histogram[value]++
+		if value >= scale.SatisfiedMin {
+			result.Satisfied++
+		}
+		switch {
+		case value >= scale.PromoterMin:
+			result.Promoters++
+		case value <= scale.DetractorMax:
+			result.Detractors++
+		default:
+			result.Passives++
+		}
+	}
+
+	if result.Count > 0 {
+		count := float64(result.Count)
+		result.Average = float64(sum) / count
+		result.CSAT = float64(result.Satisfied) * 100 / count
+		result.NPS = float64(result.Promoters-result.Detractors) * 100 / count
+	}
+
+	return result
+}
+
+// StatisticsRange retrieves all rows of a statistics report for every day of the range,
+// fetching the days concurrently. The rows are returned in the order of the days.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - dst: The API client.
+//   - query: The report parameters; the dates are replaced with the days of the range (nil - no filters).
+//   - from: The first day of the range.
+//   - to: The last day of the range (inclusive).
+//   - workers: The number of days fetched concurrently (default: 4).
+//
+// Returns:
+//   - A slice of the rows of all the days.
+//   - ErrorInvalidParameters if a date is not set or the range is empty, or the joined errors of the failed days.
+func StatisticsRange[T StatisticsRow](ctx context.Context, dst *Ctd, query *StatisticsQuery, from, to time.Time, workers int) ([]T, error) {
+	if from.IsZero() || to.IsZero() {
+		return nil, ErrorInvalidParameters
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	if to.Before(from) {
+		return nil, ErrorInvalidParameters
+	}
+	if workers <= 0 {
+		workers = 4
+	}
+
+	days := []time.Time{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+
+	results := make([][]T, len(days))
+	errs := make([]error, len(days))
+	jobs := make(chan int)
+
+	wg := sync.WaitGroup{}
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				params := statisticsQuery[T](query)
+				params.Date = days[i]
+				params.StartDate = time.Time{}
+				params.FinishDate = time.Time{}
+				params.Offset = 0
+				results[i], errs[i] = AllStatistics[T](ctx, dst, &params)
+			}
+		}()
+	}
+
+	for i := range days {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	result := []T{}
+	for _, rows := range results {
+		result = append(result, rows...)
+	}
+
+	return result, nil
+}
+
+// AllStatisticsRatingRange retrieves all statistic ratings for every day of the range.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - from: The first day of the range.
+//   - to: The last day of the range (inclusive).
+//
+// Returns:
+//   - A slice of StatisticsRating containing the ratings of all the days.
+//   - An error if the range is empty or a request fails.
+func (dst *Ctd) AllStatisticsRatingRange(ctx context.Context, from, to time.Time) ([]StatisticsRating, error) {
+	return StatisticsRange[StatisticsRating](ctx, dst, nil, from, to, 0)
+}
+
+// RatingsByOperator groups the ratings by the operators of the rated requests and calculates their metrics.
+// The requests are loaded by ValuationRequestID concurrently; the operator names are taken from AllOperators.
+// The ratings of the requests that can't be loaded are left out of the summaries.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - ratings: The ratings.
+//   - scale: The rating scale.
+//   - workers: The number of requests loaded concurrently (default: 4).
+//
+// Returns:
+//   - A slice of OperatorRatingSummary ordered by operator ID, returned also when some requests can't be loaded.
+//   - An error if the operators can't be loaded, or the joined errors of the requests that can't be loaded.
+func (dst *Ctd) RatingsByOperator(ctx context.Context, ratings []StatisticsRating, scale RatingScale, workers int) ([]OperatorRatingSummary, error) {
+	if workers <= 0 {
+		workers = 4
+	}
+
+	ids := []int64{}
+	seen := map[int64]bool{}
+	for _, rating := range ratings {
+		if rating.ValuationRequestID != 0 && !seen[rating.ValuationRequestID] {
+			seen[rating.ValuationRequestID] = true
+			ids = append(ids, rating.ValuationRequestID)
+		}
+	}
+
+	operators := make([]int64, len(ids))
+	errs := make([]error, len(ids))
+	jobs := make(chan int)
+
+	wg := sync.WaitGroup{}
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				request, err := dst.GetRequest(ctx, ids[i])
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				operators[i] = request.OperatorID
+			}
+		}()
+	}
+
+	for i := range ids {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	requests := make(map[int64]int64, len(ids))
+	failed := map[int64]bool{}
+	for i, id := range ids {
+		if errs[i] != nil {
+			failed[id] = true
+			errs[i] = fmt.Errorf("request %d: %w", id, errs[i])
+			continue
+		}
+		requests[id] = operators[i]
+	}
+	if len(failed) > 0 {
+		ratings = slices.DeleteFunc(slices.Clone(ratings), func(rating StatisticsRating) bool {
+			return failed[rating.ValuationRequestID]
+		})
+	}
+
+	result, err := dst.summarizeByOperator(ctx, ratings, requests, scale)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// summarizeByOperator groups the ratings by the operators of the requests and adds the operator names.
+func (dst *Ctd) summarizeByOperator(ctx context.Context, ratings []StatisticsRating, requests map[int64]int64, scale RatingScale) ([]OperatorRatingSummary, error) {
+	groups := map[int64][]StatisticsRating{}
+	for _, rating := range ratings {
+		operator := requests[rating.ValuationRequestID]
+		groups[operator] = append(groups[operator], rating)
+	}
+
+	names := map[int64]string{}
+	if len(groups) > 0 && (len(groups) > 1 || groups[0] == nil) {
+		// Some requests have operators
+		operators, err := dst.AllOperators(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, operator := range operators {
+			names[operator.ID] = strings.TrimSpace(operator.FirstName + " " + operator.LastName)
+		}
+	}
+
+	result := make([]OperatorRatingSummary, 0, len(groups))
+	for operator, items := range groups {
+		result = append(result, OperatorRatingSummary{
+			OperatorID:    operator,
+			Name:          names[operator],
+			RatingSummary: SummarizeRatings(items, scale),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].OperatorID < result[j].OperatorID
+	})
+
+	return result, nil
+}
@@ -0,0 +1,109 @@
+package ctd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeRatings(t *testing.T) {
+	ratings := []StatisticsRating{
+		{ScoreValue: "5"}, {ScoreValue: "5"}, {ScoreValue: "4"}, {ScoreValue: "3"}, {ScoreValue: "1"},
+		{ScoreValue: ""}, {ScoreValue: "9"}, {ScoreValue: "0", RatingScaleScore: "2"},
+	}
+
+	summary := SummarizeRatings(ratings, RatingScaleFive)
+	require.Equal(t, 6, summary.Count, "valid ratings")
+	require.Equal(t, 2, summary.Invalid, "empty and out of scale ratings")
+	require.Equal(t, map[int64]int{1: 1, 2: 1, 3: 1, 4: 1, 5: 2}, summary.Histogram, "histogram")
+	require.InDelta(t, 20.0/6, summary.Average, 0.001, "average")
+	require.InDelta(t, 50, summary.CSAT, 0.001, "CSAT")
+	require.Equal(t, 2, summary.Promoters, "promoters")
+	require.Equal(t, 1, summary.Passives, "passives")
+	require.Equal(t, 3, summary.Detractors, "detractors")
+	require.InDelta(t, -100.0/6, summary.NPS, 0.001, "NPS")
+
+	summary = SummarizeRatings(nil, RatingScaleTen)
+	require.Zero(t, summary.Count, "no ratings")
+	require.Zero(t, summary.NPS, "NPS without ratings")
+}
+
+func TestRatingsByOperator(t *testing.T) {
+	ctx := context.Background()
+
+	mu := sync.Mutex{}
+	dates := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoder := json.NewEncoder(w)
+		switch {
+		case r.URL.Path == "/v1/statistics":
+			date := r.URL.Query().Get("date")
+			mu.Lock()
+			dates = append(dates, date)
+			mu.Unlock()
+			data := []map[string]any{}
+			switch date {
+			case "2026-01-05":
+				data = append(data, map[string]any{"score_value": 5, "valuation_request_id": 1}, map[string]any{"score_value": 2, "valuation_request_id": 2})
+			case "2026-01-07":
+				data = append(data, map[string]any{"score_value": 4, "valuation_request_id": 3})
+			}
+			encoder.Encode(map[string]any{"status": "success", "data": data, "meta": map[string]any{"total": len(data)}})
+		case strings.HasPrefix(r.URL.Path, "/v1/requests/"):
+			operators := map[string]int64{"1": 7, "2": 8, "3": 7}
+			id := strings.TrimPrefix(r.URL.Path, "/v1/requests/")
+			if id == "4" {
+				http.Error(w, "bad gateway", http.StatusBadGateway)
+				return
+			}
+			encoder.Encode(map[string]any{"status": "success", "data": map[string]any{"id": 1, "operator_id": operators[id]}})
+		case r.URL.Path == "/v1/operators":
+			encoder.Encode(map[string]any{"status": "success", "data": []map[string]any{
+				{"id": 7, "first_name": "Anna", "last_name": "Ivanova"},
+				{"id": 8, "first_name": "Oleg"},
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dst := &Ctd{}
+	dst.Init(server.URL, "token")
+
+	_, err := dst.AllStatisticsRatingRange(ctx, time.Date(2026, 1, 7, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC))
+	require.ErrorIs(t, err, ErrorInvalidParameters, "reversed range should be rejected")
+	_, err = dst.AllStatisticsRatingRange(ctx, time.Time{}, time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC))
+	require.ErrorIs(t, err, ErrorInvalidParameters, "zero start should be rejected")
+	require.Empty(t, dates, "invalid ranges should not be fetched")
+
+	ratings, err := dst.AllStatisticsRatingRange(ctx, time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC), time.Date(2026, 1, 7, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err, "dst.AllStatisticsRatingRange() error")
+	require.ElementsMatch(t, []string{"2026-01-05", "2026-01-06", "2026-01-07"}, dates, "every day should be fetched")
+	require.Len(t, ratings, 3, "ratings of all the days")
+	require.Equal(t, int64(3), ratings[2].ValuationRequestID, "ratings should keep the order of the days")
+
+	summaries, err := dst.RatingsByOperator(ctx, ratings, RatingScaleFive, 2)
+	require.NoError(t, err, "dst.RatingsByOperator() error")
+	require.Len(t, summaries, 2, "summaries by operator")
+	require.Equal(t, int64(7), summaries[0].OperatorID, "summaries should be ordered by operator")
+	require.Equal(t, "Anna Ivanova", summaries[0].Name, "operator name")
+	require.Equal(t, 2, summaries[0].Count, "ratings of operator 7")
+	require.InDelta(t, 100, summaries[0].CSAT, 0.001, "CSAT of operator 7")
+	require.Equal(t, "Oleg", summaries[1].Name, "operator name")
+	require.Equal(t, 1, summaries[1].Detractors, "detractors of operator 8")
+
+	summaries, err = dst.RatingsByOperator(ctx, append(ratings, StatisticsRating{ScoreValue: "1", ValuationRequestID: 4}), RatingScaleFive, 2)
+	require.Error(t, err, "failed request should be reported")
+	require.Contains(t, err.Error(), "request 4", "error should name the request")
+	require.Len(t, summaries, 2, "other requests should be summarized")
+	require.Equal(t, 2, summaries[0].Count, "ratings of operator 7")
+	require.Equal(t, 1, summaries[1].Count, "rating of the failed request should be left out")
+}
@@ -0,0 +1,318 @@
+package ctd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	RedactModeMask = "mask" // Replace letters and digits of the detected value with '*'
+	RedactModeHash = "hash" // Replace the detected value with a salted hash, so values remain joinable
+)
+
+// RedactDetector describes a single kind of personal data to be redacted.
+// Matches are found by Pattern and, if Validate is set, confirmed by it.
+// Normalize is used in hash mode so that different spellings of the same value
+// (e.g. "+7 701 763-04-65" and "77017630465") produce the same hash.
+type RedactDetector struct {
+	Name      string                  // Name: Detector name, used as the hash prefix (e.g. "phone")
+	Pattern   *regexp.Regexp          // Pattern: Regular expression matching candidate values
+	Validate  func(str string) bool   // Validate: Optional validation of a candidate value
+	Normalize func(str string) string // Normalize: Optional normalization of a value before hashing
+}
+
+// PhoneDetector returns a detector for phone numbers (10 to 15 digits with optional separators).
+func PhoneDetector() *RedactDetector {
+	return &RedactDetector{
+		Name:    "phone",
+		Pattern: regexp.MustCompile(`\+?\d[\d\s\-()]{8,18}\d`),
+		Validate: func(str string) bool {
+			digits := onlyDigits(str)
+			return len(digits) >= 10 && len(digits) <= 15
+		},
+		Normalize: onlyDigits,
+	}
+}
+
+// EmailDetector returns a detector for email addresses.
+func EmailDetector() *RedactDetector {
+	return &RedactDetector{
+		Name:      "email",
+		Pattern:   regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+		Normalize: strings.ToLower,
+	}
+}
+
+// CardDetector returns a detector for payment card numbers (13 to 19 digits passing the Luhn check).
+func CardDetector() *RedactDetector {
+	return &RedactDetector{
+		Name:    "card",
+		Pattern: regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`),
+		Validate: func(str string) bool {
+			return luhnValid(onlyDigits(str))
+		},
+		Normalize: onlyDigits,
+	}
+}
+
+// PassportDetector returns a detector for passport-like identifiers
+// (one or two latin letters followed by 6 to 9 digits, e.g. "N12345678").
+func PassportDetector() *RedactDetector {
+	return &RedactDetector{
+		Name:    "passport",
+		Pattern: regexp.MustCompile(`\b[A-Z]{1,2}\s?\d{6,9}\b`),
+		Normalize: func(str string) string {
+			return strings.ToUpper(strings.ReplaceAll(str, " ", ""))
+		},
+	}
+}
+
+// RegexDetector returns a detector for a custom regular expression.
+//
+// Parameters:
+//   - name: The detector name.
+//   - pattern: The regular expression.
+//
+// Returns:
+//   - A pointer to a RedactDetector.
+//   - An error if the regular expression is invalid.
+func RegexDetector(name, pattern string) (*RedactDetector, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedactDetector{
+		Name:    name,
+		Pattern: re,
+	}, nil
+}
+
+// Redactor removes personal data from texts, messages, client records and log output.
+// Detectors are applied in order; when matches overlap the earlier detector wins.
+type Redactor struct {
+	Detectors []*RedactDetector // Detectors: Detectors to apply (default: email, card, phone, passport)
+	Mode      string            // Mode: Redaction mode ('mask' (default) or 'hash')
+	Salt      string            // Salt: Salt used in hash mode
+	KeepLast  int               // KeepLast: Number of trailing letters or digits left visible in mask mode
+}
+
+// NewRedactor creates a new Redactor with the specified mode and detectors.
+// If no detectors are provided, the email, card, phone and passport detectors are used.
+//
+// Parameters:
+//   - mode: The redaction mode ('mask' or 'hash').
+//   - detectors: Optional list of detectors.
+//
+// Returns:
+//   - A pointer to a Redactor.
+func NewRedactor(mode string, detectors ...*RedactDetector) *Redactor {
+	if len(detectors) == 0 {
+		detectors = []*RedactDetector{EmailDetector(), CardDetector(), PhoneDetector(), PassportDetector()}
+	}
+
+	return &Redactor{
+		Detectors: detectors,
+		Mode:      mode,
+	}
+}
+
+// Redact replaces every detected value in the string.
+//
+// Parameters:
+//   - str: The string to redact.
+//
+// Returns:
+//   - The redacted string.
+func (dst *Redactor) Redact(str string) string {
+	if dst == nil || str == "" {
+		return str
+	}
+
+	type match struct {
+		start, end int
+		detector   *RedactDetector
+	}
+
+	matches := []match{}
+	taken := func(start, end int) bool {
+		for _, m := range matches {
+			if start < m.end && end > m.start {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, detector := range dst.Detectors {
+		if detector == nil || detector.Pattern == nil {
+			continue
+		}
+		for _, loc := range detector.Pattern.FindAllStringIndex(str, -1) {
+			if taken(loc[0], loc[1]) {
+				continue
+			}
+			if detector.Validate != nil && !detector.Validate(str[loc[0]:loc[1]]) {
+				continue
+			}
+			matches = append(matches, match{start: loc[0], end: loc[1], detector: detector})
+		}
+	}
+
+	if len(matches) == 0 {
+		return str
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].start < matches[j].start
+	})
+
+	var sb strings.Builder
+	last := 0
+	for _, m := range matches {
+		sb.WriteString(str[last:m.start])
+		sb.WriteString(dst.replace(m.detector, str[m.start:m.end]))
+		last = m.end
+	}
+	sb.WriteString(str[last:])
+
+	return sb.String()
+}
+
+// RedactValue redacts the whole value as if it was detected by the named detector.
+// It is used for fields that are personal data by definition, such as client phones.
+//
+// Parameters:
+//   - name: The detector name (e.g. "phone").
+//   - value: The value to redact.
+//
+// Returns:
+//   - The redacted value.
+func (dst *Redactor) RedactValue(name, value string) string {
+	if dst == nil || value == "" {
+		return value
+	}
+
+	for _, detector := range dst.Detectors {
+		if detector != nil && detector.Name == name {
+			return dst.replace(detector, value)
+		}
+	}
+
+	return dst.replace(&RedactDetector{Name: name}, value)
+}
+
+// RedactMessage redacts the text of the message in place.
+func (dst *Redactor) RedactMessage(message *Message) {
+	if dst == nil || message == nil {
+		return
+	}
+
+	message.Text = dst.Redact(message.Text)
+	message.AiTips = dst.Redact(message.AiTips)
+}
+
+// RedactRequestMessage redacts the text of the request message in place.
+func (dst *Redactor) RedactRequestMessage(message *RequestMessage) {
+	if dst == nil || message == nil {
+		return
+	}
+
+	message.Text = dst.Redact(message.Text)
+}
+
+// RedactClient redacts the personal data of the client in place.
+// Phone fields are redacted as a whole, free text fields are scanned by the detectors.
+func (dst *Redactor) RedactClient(client *Client) {
+	if dst == nil || client == nil {
+		return
+	}
+
+	client.Phone = dst.RedactValue("phone", client.Phone)
+	client.ClientPhone = dst.RedactValue("phone", client.ClientPhone)
+	client.Name = dst.Redact(client.Name)
+	client.Username = dst.Redact(client.Username)
+	client.AssignedName = dst.Redact(client.AssignedName)
+	client.Comment = dst.Redact(client.Comment)
+	client.ExtraComment1 = dst.Redact(client.ExtraComment1)
+	client.ExtraComment2 = dst.Redact(client.ExtraComment2)
+	client.ExtraComment3 = dst.Redact(client.ExtraComment3)
+	client.FirstClientMessageStr = dst.Redact(client.FirstClientMessageStr)
+	client.LastClientMessageStr = dst.Redact(client.LastClientMessageStr)
+}
+
+// RedactTranscript redacts the text lines of the transcript in place.
+func (dst *Redactor) RedactTranscript(transcript *Transcript) {
+	if dst == nil || transcript == nil {
+		return
+	}
+
+	for i := range transcript.Lines {
+		if transcript.Lines[i].Format == "text" {
+			transcript.Lines[i].Text = dst.Redact(transcript.Lines[i].Text)
+		}
+	}
+}
+
+func (dst *Redactor) replace(detector *RedactDetector, value string) string {
+	if strings.ToLower(dst.Mode) == RedactModeHash {
+		normalized := value
+		if detector.Normalize != nil {
+			normalized = detector.Normalize(value)
+		}
+		sum := sha256.Sum256([]byte(dst.Salt + normalized))
+		return fmt.Sprintf("[%s:%s]", detector.Name, hex.EncodeToString(sum[:])[:12])
+	}
+
+	runes := []rune(value)
+	keep := dst.KeepLast
+	for i := len(runes) - 1; i >= 0; i-- {
+		if !unicode.IsLetter(runes[i]) && !unicode.IsDigit(runes[i]) {
+			continue
+		}
+		if keep > 0 {
+			keep--
+			continue
+		}
+		runes[i] = '*'
+	}
+
+	return string(runes)
+}
+
+func onlyDigits(str string) string {
+	var sb strings.Builder
+	for _, r := range str {
+		if r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func luhnValid(digits string) bool {
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
@@ -0,0 +1,88 @@
+package ctd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactor_Redact(t *testing.T) {
+	tests := []struct {
+		name     string
+		redactor *Redactor
+		input    string
+		want     string
+	}{
+		{
+			name:     "Phone mask",
+			redactor: NewRedactor(RedactModeMask),
+			input:    "Call me at +7 701 763-04-65 please",
+			want:     "Call me at +* *** ***-**-** please",
+		},
+		{
+			name:     "Email mask with kept digits",
+			redactor: &Redactor{Detectors: []*RedactDetector{EmailDetector()}, KeepLast: 3},
+			input:    "Mail: john.doe@example.com",
+			want:     "Mail: ****.***@*******.com",
+		},
+		{
+			name:     "Card number",
+			redactor: NewRedactor(RedactModeMask),
+			input:    "Card 4111 1111 1111 1111 is mine",
+			want:     "Card **** **** **** **** is mine",
+		},
+		{
+			name:     "Not a card number (Luhn check fails)",
+			redactor: NewRedactor(RedactModeMask, CardDetector()),
+			input:    "Order 4111 1111 1111 1112",
+			want:     "Order 4111 1111 1111 1112",
+		},
+		{
+			name:     "Passport",
+			redactor: NewRedactor(RedactModeMask),
+			input:    "Passport N1234567",
+			want:     "Passport ********",
+		},
+		{
+			name:     "Nil redactor",
+			redactor: nil,
+			input:    "+77017630465",
+			want:     "+77017630465",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.redactor.Redact(tt.input), "Redactor.Redact()")
+		})
+	}
+
+	t.Run("Hash mode is joinable", func(t *testing.T) {
+		redactor := NewRedactor(RedactModeHash)
+		redactor.Salt = "salt"
+		first := redactor.Redact("+7 701 763-04-65")
+		second := redactor.RedactValue("phone", "77017630465")
+		require.True(t, strings.HasPrefix(first, "[phone:"), "Redactor.Redact() should return hashed phone")
+		require.Equal(t, first, second, "the same phone should produce the same hash")
+	})
+
+	t.Run("Custom regex", func(t *testing.T) {
+		detector, err := RegexDetector("contract", `CN-\d{5}`)
+		require.NoError(t, err, "RegexDetector() error")
+		redactor := NewRedactor(RedactModeMask, detector)
+		require.Equal(t, "Contract **-*****", redactor.Redact("Contract CN-12345"), "Redactor.Redact()")
+
+		_, err = RegexDetector("broken", `(`)
+		require.Error(t, err, "RegexDetector() should fail on invalid pattern")
+	})
+
+	t.Run("Client", func(t *testing.T) {
+		redactor := NewRedactor(RedactModeMask)
+		client := Client{ID: 1, Name: "John", Phone: "77017630465", ClientPhone: "+7 701 763 0465", Comment: "email john@example.com"}
+		redactor.RedactClient(&client)
+		require.Equal(t, "***********", client.Phone, "client phone should be redacted")
+		require.Equal(t, "+* *** *** ****", client.ClientPhone, "client phone should be redacted")
+		require.Equal(t, "email ****@*******.***", client.Comment, "client comment should be redacted")
+		require.Equal(t, "John", client.Name, "client name should not be changed")
+	})
+}
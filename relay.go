@@ -0,0 +1,295 @@
+package ctd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// RelaySubscriber describes a downstream HTTP endpoint receiving the relayed webhooks.
+type RelaySubscriber struct {
+	Name       string            `json:"name"`       // Name: Name of the subscriber used in the delivery log
+	URL        string            `json:"url"`        // URL: Endpoint the webhooks are posted to
+	Events     []string          `json:"events"`     // Events: Hook types to relay (empty - all)
+	Channels   []int64           `json:"channels"`   // Channels: Channel IDs to relay (empty - all)
+	Headers    map[string]string `json:"headers"`    // Headers: Additional request headers (e.g., authorization)
+	Retries    int               `json:"retries"`    // Retries: Number of retries of failed deliveries (default: 3)
+	QueueSize  int               `json:"queue_size"` // QueueSize: Capacity of the subscriber queue (default: 1000)
+	RetryDelay time.Duration     `json:"-"`          // RetryDelay: Delay before the first retry, doubled on every next one (default: 1 second)
+	Timeout    time.Duration     `json:"-"`          // Timeout: Request timeout (default: 10 seconds)
+}
+
+// Matches reports whether the notification must be relayed to the subscriber.
+func (dst *RelaySubscriber) Matches(notification *WebhookNotification) bool {
+	if len(dst.Events) > 0 && !slices.Contains(dst.Events, notification.HookType) {
+		return false
+	}
+	if len(dst.Channels) > 0 && !slices.Contains(dst.Channels, notification.ChannelID) {
+		return false
+	}
+	return true
+}
+
+// RelayDelivery is a delivery log record of a relayed webhook.
+type RelayDelivery struct {
+	Subscriber string        // Subscriber: Name of the subscriber
+	Key        string        // Key: Key of the notification
+	HookType   string        // HookType: Hook type of the notification
+	Attempts   int           // Attempts: Number of attempts made
+	StatusCode int           // StatusCode: HTTP status of the last attempt (0 if no response)
+	Error      error         // Error: Final error, nil on success
+	Duration   time.Duration // Duration: Total delivery time including retries
+	Time       time.Time     // Time: Time when the delivery finished
+}
+
+type relayTarget struct {
+	RelaySubscriber
+	client *http.Client
+	queue  chan *WebhookNotification
+}
+
+// Relay receives Chat2Desk webhooks once and forwards them to several subscribers.
+// Every subscriber has its own queue and worker, so a slow subscriber doesn't delay the others.
+// Failed deliveries are retried on network errors, 429 and 5xx responses.
+// If the queue of a subscriber is full, the notification is dropped for this subscriber and logged.
+// Relay is usually wrapped by WebhookVerifier.Handler.
+type Relay struct {
+	OnDelivery func(delivery RelayDelivery) // OnDelivery: Optional callback called for every delivery log record
+	LogSize    int                          // LogSize: Number of the delivery log records kept in memory (default: 1000)
+
+	targets []*relayTarget
+	mu      sync.RWMutex
+	closed  bool
+	log     []RelayDelivery
+	wg      sync.WaitGroup
+}
+
+// NewRelay creates a new Relay and starts the workers of the subscribers.
+//
+// Parameters:
+//   - subscribers: The downstream subscribers.
+//
+// Returns:
+//   - A pointer to a Relay.
+//   - ErrorInvalidParameters if a subscriber has no URL.
+func NewRelay(subscribers []RelaySubscriber) (*Relay, error) {
+	relay := &Relay{}
+
+	for _, subscriber := range subscribers {
+		if subscriber.URL == "" {
+			return nil, ErrorInvalidParameters
+		}
+		if subscriber.Name == "" {
+			subscriber.Name = subscriber.URL
+		}
+		if subscriber.Retries <= 0 {
+			subscriber.Retries = 3
+		}
+		if subscriber.QueueSize <= 0 {
+			subscriber.QueueSize = 1000
+		}
+		if subscriber.RetryDelay <= 0 {
+			subscriber.RetryDelay = time.Second
+		}
+		if subscriber.Timeout <= 0 {
+			subscriber.Timeout = 10 * time.Second
+		}
+
+		relay.targets = append(relay.targets, &relayTarget{
+			RelaySubscriber: subscriber,
+			client:          &http.Client{Timeout: subscriber.Timeout},
+			queue:           make(chan *WebhookNotification, subscriber.QueueSize),
+		})
+	}
+
+	for _, target := range relay.targets {
+		relay.wg.Add(1)
+		go relay.work(target)
+	}
+
+	return relay, nil
+}
+
+// Relay queues the notification for all the matching subscribers.
+// Its signature matches the callback of WebhookVerifier.Handler.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - notification: The webhook notification.
+//
+// Returns:
+//   - ErrorDispatcherClosed if the relay is shut down.
+func (dst *Relay) Relay(ctx context.Context, notification *WebhookNotification) error {
+	dst.mu.RLock()
+	if dst.closed {
+		dst.mu.RUnlock()
+		return ErrorDispatcherClosed
+	}
+
+	dropped := []RelayDelivery{}
+	for _, target := range dst.targets {
+		if !target.Matches(notification) {
+			continue
+		}
+		select {
+		case target.queue <- notification:
+		default:
+			dropped = append(dropped, RelayDelivery{
+				Subscriber: target.Name,
+				Key:        notification.Key(),
+				HookType:   notification.HookType,
+				Error:      ErrorDispatcherFull,
+				Time:       time.Now(),
+			})
+		}
+	}
+	dst.mu.RUnlock()
+
+	for _, delivery := range dropped {
+		dst.record(delivery)
+	}
+
+	return nil
+}
+
+// Deliveries returns the recent delivery log records, oldest first.
+func (dst *Relay) Deliveries() []RelayDelivery {
+	dst.mu.RLock()
+	defer dst.mu.RUnlock()
+
+	return append([]RelayDelivery{}, dst.log...)
+}
+
+// Pending returns the number of queued notifications of every subscriber.
+func (dst *Relay) Pending() map[string]int {
+	pending := make(map[string]int, len(dst.targets))
+	for _, target := range dst.targets {
+		pending[target.Name] = len(target.queue)
+	}
+	return pending
+}
+
+// Shutdown stops accepting new notifications and waits until the queued ones are delivered.
+//
+// Parameters:
+//   - ctx: The context limiting the waiting time.
+//
+// Returns:
+//   - The context error if the context is canceled before all the notifications are delivered.
+func (dst *Relay) Shutdown(ctx context.Context) error {
+	dst.mu.Lock()
+	if !dst.closed {
+		dst.closed = true
+		for _, target := range dst.targets {
+			close(target.queue)
+		}
+	}
+	dst.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		dst.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// work delivers the notifications of a subscriber one by one.
+func (dst *Relay) work(target *relayTarget) {
+	defer dst.wg.Done()
+
+	for notification := range target.queue {
+		dst.record(dst.deliver(target, notification))
+	}
+}
+
+// deliver posts the notification to the subscriber retrying failed attempts.
+func (dst *Relay) deliver(target *relayTarget, notification *WebhookNotification) RelayDelivery {
+	delivery := RelayDelivery{
+		Subscriber: target.Name,
+		Key:        notification.Key(),
+		HookType:   notification.HookType,
+	}
+	start := time.Now()
+	delay := target.RetryDelay
+
+	for {
+		delivery.Attempts++
+
+		retry := false
+		delivery.StatusCode, delivery.Error = target.post(notification)
+		switch {
+		case delivery.Error != nil:
+			retry = true
+		case delivery.StatusCode == http.StatusTooManyRequests || delivery.StatusCode >= 500:
+			delivery.Error = fmt.Errorf("subscriber responded with status %d", delivery.StatusCode)
+			retry = true
+		case delivery.StatusCode >= 300:
+			delivery.Error = fmt.Errorf("subscriber responded with status %d", delivery.StatusCode)
+		}
+
+		if !retry || delivery.Attempts > target.Retries {
+			break
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+
+	delivery.Duration = time.Since(start)
+	delivery.Time = time.Now()
+
+	return delivery
+}
+
+// post sends a single delivery attempt.
+func (dst *relayTarget) post(notification *WebhookNotification) (int, error) {
+	req, err := http.NewRequest("POST", dst.URL, bytes.NewReader(notification.Raw))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Ctd-Hook-Type", notification.HookType)
+	req.Header.Set("X-Ctd-Event-Key", notification.Key())
+	for key, value := range dst.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := dst.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, nil
+}
+
+// record adds the delivery to the log and calls the callback.
+func (dst *Relay) record(delivery RelayDelivery) {
+	size := dst.LogSize
+	if size <= 0 {
+		size = 1000
+	}
+
+	dst.mu.Lock()
+	dst.log = append(dst.log, delivery)
+	if len(dst.log) > size {
+		dst.log = dst.log[len(dst.log)-size:]
+	}
+	dst.mu.Unlock()
+
+	if dst.OnDelivery != nil {
+		dst.OnDelivery(delivery)
+	}
+}
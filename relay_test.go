@@ -0,0 +1,81 @@
+package ctd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelay(t *testing.T) {
+	ctx := context.Background()
+
+	mu := sync.Mutex{}
+	received := map[string][]string{}
+	failures := 1
+	handler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			defer mu.Unlock()
+			if name == "flaky" && failures > 0 {
+				failures--
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			received[name] = append(received[name], r.Header.Get("X-Ctd-Hook-Type")+" "+string(body))
+		}
+	}
+
+	all := httptest.NewServer(handler("all"))
+	defer all.Close()
+	flaky := httptest.NewServer(handler("flaky"))
+	defer flaky.Close()
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer rejecting.Close()
+
+	_, err := NewRelay([]RelaySubscriber{{Name: "empty"}})
+	require.ErrorIs(t, err, ErrorInvalidParameters, "NewRelay() should require the URL")
+
+	relay, err := NewRelay([]RelaySubscriber{
+		{Name: "all", URL: all.URL},
+		{Name: "flaky", URL: flaky.URL, Events: []string{"inbox"}, Channels: []int64{10}, RetryDelay: time.Millisecond},
+		{Name: "rejecting", URL: rejecting.URL, Events: []string{"outbox"}, RetryDelay: time.Millisecond},
+	})
+	require.NoError(t, err, "NewRelay() error")
+
+	for _, body := range []string{
+		`{"hook_type":"inbox","message_id":1,"channel_id":10}`,
+		`{"hook_type":"inbox","message_id":2,"channel_id":20}`,
+		`{"hook_type":"outbox","message_id":3,"channel_id":10}`,
+	} {
+		notification, err := ParseWebhookNotification([]byte(body))
+		require.NoError(t, err, "ParseWebhookNotification() error")
+		require.NoError(t, relay.Relay(ctx, notification), "relay.Relay() error")
+	}
+
+	require.NoError(t, relay.Shutdown(ctx), "relay.Shutdown() error")
+	require.ErrorIs(t, relay.Relay(ctx, &WebhookNotification{}), ErrorDispatcherClosed, "closed relay should reject notifications")
+
+	require.Len(t, received["all"], 3, "subscriber without filters should receive all notifications")
+	require.Equal(t, []string{`inbox {"hook_type":"inbox","message_id":1,"channel_id":10}`}, received["flaky"], "subscriber should receive filtered notifications")
+
+	deliveries := map[string][]RelayDelivery{}
+	for _, delivery := range relay.Deliveries() {
+		deliveries[delivery.Subscriber] = append(deliveries[delivery.Subscriber], delivery)
+	}
+	require.Len(t, deliveries["flaky"], 1, "delivery log")
+	require.Equal(t, 2, deliveries["flaky"][0].Attempts, "failed delivery should be retried")
+	require.NoError(t, deliveries["flaky"][0].Error, "retried delivery should succeed")
+	require.Len(t, deliveries["rejecting"], 1, "delivery log")
+	require.Equal(t, 1, deliveries["rejecting"][0].Attempts, "rejected delivery should not be retried")
+	require.Equal(t, http.StatusBadRequest, deliveries["rejecting"][0].StatusCode, "delivery status")
+	require.Error(t, deliveries["rejecting"][0].Error, "rejected delivery should fail")
+}
@@ -2,12 +2,150 @@ package ctd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
 	"time"
 )
 
+type RequestResponse struct {
+	BasicResponse
+	Data Request `json:"data"` // Data: Request item
+}
+
+type RequestsResponse struct {
+	BasicResponse
+	Data []Request    `json:"data"` // Data: List of requests
+	Meta MetaResponse `json:"meta"` // Meta: Meta information
+}
+
+// Request represents a single request (ticket) in the Chat2Desk API.
+// A request is a part of a dialog between its opening and closing and is the unit
+// rated by clients and tagged by operators.
+type Request struct {
+	ID         int64       `json:"id"`          // ID: Request ID
+	ClientID   int64       `json:"client_id"`   // ClientID: Client ID associated with the request
+	DialogID   int64       `json:"dialog_id"`   // DialogID: Dialog ID associated with the request
+	OperatorID int64       `json:"operator_id"` // OperatorID: Operator ID associated with the request
+	ChannelID  int64       `json:"channel_id"`  // ChannelID: Channel ID associated with the request
+	Transport  string      `json:"transport"`   // Transport: Transport of the request
+	State      string      `json:"state"`       // State: Request state ('open' or 'closed')
+	Start      string      `json:"start"`       // Start: Request opening time
+	End        string      `json:"end"`         // End: Request closing time (empty for open requests)
+	Duration   json.Number `json:"duration"`    // Duration: Request duration in seconds
+	Rating     json.Number `json:"rating"`      // Rating: Request rating given by the client (empty if not rated)
+	Messages   json.Number `json:"messages"`    // Messages: Number of messages in the request
+	Tags       []Tag       `json:"tags"`        // Tags: List of tags assigned to the request
+}
+
+// StartTime returns the opening time of the request as a time.Time object.
+//
+// Returns:
+//   - time.Time: The opening time of the request or zero time if it can't be parsed.
+func (dst *Request) StartTime() time.Time {
+	return parseTime(dst.Start)
+}
+
+// EndTime returns the closing time of the request as a time.Time object.
+//
+// Returns:
+//   - time.Time: The closing time of the request or zero time if the request is open.
+func (dst *Request) EndTime() time.Time {
+	return parseTime(dst.End)
+}
+
+// GetDuration returns the duration of the request.
+// If the API doesn't provide the duration, it is calculated from the opening and closing times.
+//
+// Returns:
+//   - time.Duration: The duration of the request or zero if it is unknown.
+func (dst *Request) GetDuration() time.Duration {
+	if seconds, err := dst.Duration.Int64(); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	start, end := dst.StartTime(), dst.EndTime()
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+
+	return end.Sub(start)
+}
+
+// GetRating returns the rating of the request.
+// If the request is not rated or the rating can't be converted, it returns -1.
+//
+// Returns:
+//   - An int64 representing the rating, or -1 if the request is not rated.
+func (dst *Request) GetRating() int64 {
+	if result, err := dst.Rating.Int64(); err == nil {
+		return result
+	}
+
+	return -1
+}
+
+// IsClosed reports whether the request is closed.
+func (dst *Request) IsClosed() bool {
+	return dst.State == "closed" || dst.End != ""
+}
+
+type GetRequestsParams struct {
+	Limit      int       `json:"limit,omitempty"`       // Limit: Optional limit of requests to retrieve (default: 20, max: 200)
+	Offset     int       `json:"offset,omitempty"`      // Offset: Optional offset for pagination (default: 0)
+	ClientID   int64     `json:"client_id,omitempty"`   // ClientID: Optional filter by client ID
+	DialogID   int64     `json:"dialog_id,omitempty"`   // DialogID: Optional filter by dialog ID
+	OperatorID int64     `json:"operator_id,omitempty"` // OperatorID: Optional filter by operator ID
+	StartDate  time.Time `json:"start_date,omitempty"`  // StartDate: Optional filter by opening date (from)
+	FinishDate time.Time `json:"finish_date,omitempty"` // FinishDate: Optional filter by opening date (to)
+	Tags       []int64   `json:"tags,omitempty"`        // Tags: Optional filter by tag IDs
+	Order      string    `json:"order,omitempty"`       // Order: Optional order of results ('asc' or 'desc', default: '')
+}
+
+func (p *GetRequestsParams) Params() string {
+	params := []string{}
+
+	if p.Limit > 0 {
+		params = append(params, fmt.Sprintf("limit=%d", p.Limit))
+	}
+	if p.Offset > 0 {
+		params = append(params, fmt.Sprintf("offset=%d", p.Offset))
+	}
+	if p.ClientID > 0 {
+		params = append(params, fmt.Sprintf("client_id=%d", p.ClientID))
+	}
+	if p.DialogID > 0 {
+		params = append(params, fmt.Sprintf("dialog_id=%d", p.DialogID))
+	}
+	if p.OperatorID > 0 {
+		params = append(params, fmt.Sprintf("operator_id=%d", p.OperatorID))
+	}
+	if !p.StartDate.IsZero() {
+		params = append(params, fmt.Sprintf("start_date=%s", p.StartDate.Format("2006-01-02")))
+	}
+	if !p.FinishDate.IsZero() {
+		params = append(params, fmt.Sprintf("finish_date=%s", p.FinishDate.Format("2006-01-02")))
+	}
+	if len(p.Tags) > 0 {
+		tags := make([]string, 0, len(p.Tags))
+		for _, tag := range p.Tags {
+			tags = append(tags, fmt.Sprintf("%d", tag))
+		}
+		params = append(params, fmt.Sprintf("tags=%s", strings.Join(tags, ",")))
+	}
+	if p.Order != "" {
+		if p.Order == "asc" || p.Order == "desc" {
+			params = append(params, fmt.Sprintf("order=%s", p.Order))
+		}
+	}
+
+	if len(params) > 0 {
+		return "?" + strings.Join(params, "&")
+	}
+	return ""
+}
+
 type RequestMessageExtraData struct {
 	SystemType string `json:"system_type"`
 }
@@ -166,3 +304,197 @@ func (dst *Ctd) RequestMessages(ctx context.Context, request int64) ([]RequestMe
 
 	return messages, nil
 }
+
+// APIGetRequest retrieves a request by its ID from the Chat2Desk API.
+// It takes a context and a request ID, and returns a RequestResponse or an error.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - request: The ID of the request to retrieve.
+//
+// Returns:
+//   - A pointer to a RequestResponse containing the response data.
+//   - An error if the request fails or if the response is invalid.
+func (dst *Ctd) APIGetRequest(ctx context.Context, request int64) (*RequestResponse, error) {
+	url := fmt.Sprintf("%sv1/requests/%d", dst.Url, request)
+	response := RequestResponse{}
+
+	body, err := dst.doRequest(ctx, "GET", url, nil, &response)
+	if err == ErrorInvalidResponse {
+		if strings.Contains(string(body), "not_found") {
+			dst.Error(ctx, "Invalid request ID: %d", request)
+			return nil, ErrorInvalidRequestID
+		}
+	}
+
+	if err != nil {
+		dst.Error(ctx, "Failed to get request: %v", err)
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// APIGetRequests retrieves a list of requests from the Chat2Desk API.
+// It takes a context and GetRequestsParams, and returns a RequestsResponse or an error.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - params: The parameters for filtering and pagination (nil - no filters).
+//
+// Returns:
+//   - A pointer to a RequestsResponse containing the response data.
+//   - An error if the request fails or if the response is invalid.
+func (dst *Ctd) APIGetRequests(ctx context.Context, params *GetRequestsParams) (*RequestsResponse, error) {
+	if params == nil {
+		params = &GetRequestsParams{}
+	}
+
+	url := fmt.Sprintf("%sv1/requests%s", dst.Url, params.Params())
+	response := RequestsResponse{}
+
+	if _, err := dst.doRequest(ctx, "GET", url, nil, &response); err != nil {
+		dst.Error(ctx, "Failed to get requests: %v", err)
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// GetRequest retrieves a request by its ID.
+// It uses the APIGetRequest method to fetch the request and handles errors.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - request: The ID of the request to retrieve.
+//
+// Returns:
+//   - A pointer to a Request containing the request data.
+//   - An error if the request fails, if the request is not found or if the response is invalid.
+func (dst *Ctd) GetRequest(ctx context.Context, request int64) (*Request, error) {
+	data, err := dst.APIGetRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Status != "success" {
+		dst.Error(ctx, "Failed to get request by ID: %s", data.Errors)
+		if data.Message == "not_found" || strings.Contains(fmt.Sprintf("%s", data.Errors), "not found") {
+			return nil, ErrorInvalidRequestID
+		}
+		return nil, ErrorInvalidParameters
+	}
+
+	return &data.Data, nil
+}
+
+// GetRequests retrieves a list of requests filtered by the provided parameters.
+// It uses the APIGetRequests method to fetch the requests and handles errors.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - params: The parameters for filtering and pagination (nil - no filters).
+//
+// Returns:
+//   - A slice of Request containing the requests.
+//   - The total number of requests available (for pagination).
+//   - An error if the request fails or if the response is invalid.
+func (dst *Ctd) GetRequests(ctx context.Context, params *GetRequestsParams) ([]Request, int, error) {
+	data, err := dst.APIGetRequests(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if data.Status != "success" {
+		dst.Error(ctx, "Failed to get requests: %s", data.Errors)
+		return nil, 0, ErrorInvalidParameters
+	}
+
+	return data.Data, data.Meta.Total, nil
+}
+
+// AllRequests retrieves all requests matching the provided parameters by handling pagination.
+// The Limit and Offset of the parameters are ignored.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - params: The parameters for filtering.
+//
+// Returns:
+//   - A slice of Request containing all the requests.
+//   - An error if the request fails or if the response is invalid.
+func (dst *Ctd) AllRequests(ctx context.Context, params *GetRequestsParams) ([]Request, error) {
+	query := GetRequestsParams{}
+	if params != nil {
+		query = *params
+	}
+	query.Offset = 0
+	query.Limit = 200
+
+	requests := []Request{}
+	for {
+		data, total, err := dst.GetRequests(ctx, &query)
+		if err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, data...)
+		query.Offset += query.Limit
+		if len(data) < query.Limit || (total > 0 && query.Offset >= total) {
+			break
+		}
+	}
+
+	return requests, nil
+}
+
+// CloseRequest closes a request by closing the dialog it belongs to.
+// In Chat2Desk a request ends when its dialog is closed, so the request is fetched
+// with GetRequest first and then its dialog is closed with CloseDialog.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - request: The ID of the request to close.
+//   - operator_id: The ID of the operator closing the request.
+//
+// Returns:
+//   - An error if the request fails, if the request is already closed (ErrorDialogClosed) or if the response is invalid.
+func (dst *Ctd) CloseRequest(ctx context.Context, request, operator_id int64) error {
+	data, err := dst.GetRequest(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	if data.IsClosed() {
+		return ErrorDialogClosed
+	}
+
+	if operator_id == 0 {
+		operator_id = data.OperatorID
+	}
+
+	return dst.CloseDialog(ctx, data.DialogID, operator_id, 0)
+}
+
+// parseTime parses a time string returned by the Chat2Desk API.
+// It supports the formats used by the different API versions and returns zero time if the string can't be parsed.
+func parseTime(str string) time.Time {
+	if str == "" {
+		return time.Time{}
+	}
+
+	layouts := []string{
+		"2006-01-02T15:04:05 MST",
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+	}
+	for _, layout := range layouts {
+		if result, err := time.Parse(layout, str); err == nil {
+			return result
+		}
+	}
+
+	return time.Time{}
+}
@@ -1,7 +1,12 @@
 package ctd
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/ra-company/env"
 	"github.com/stretchr/testify/require"
@@ -61,3 +66,122 @@ func TestCtd_RequestMessages(t *testing.T) {
 		})
 	}
 }
+
+func TestCtd_GetRequest(t *testing.T) {
+	ctx := t.Context()
+	url, token := getCredentials(t)
+
+	requestID := env.GetEnvInt("API_REQUEST_ID", 0)
+	require.NotEqual(t, 0, requestID, "API_REQUEST_ID must be set in .env file or .settings")
+
+	t.Run("01 GetRequest Incorrect token", func(t *testing.T) {
+		dst := &Ctd{}
+		dst.Init(url, "incorrect_token")
+		got, err := dst.GetRequest(ctx, int64(requestID))
+		require.ErrorIs(t, err, ErrorInvalidToken, "dst.GetRequest() error")
+		require.Nil(t, got, "dst.GetRequest() should return nil data on error")
+	})
+
+	t.Run("02 GetRequest Incorrect ID", func(t *testing.T) {
+		dst := &Ctd{}
+		dst.Init(url, token)
+		got, err := dst.GetRequest(ctx, 1)
+		require.ErrorIs(t, err, ErrorInvalidRequestID, "dst.GetRequest() error")
+		require.Nil(t, got, "dst.GetRequest() should return nil data on error")
+	})
+
+	var clientID int64
+	t.Run("03 GetRequest Correct ID", func(t *testing.T) {
+		dst := &Ctd{}
+		dst.Init(url, token)
+		got, err := dst.GetRequest(ctx, int64(requestID))
+		require.NoError(t, err, "dst.GetRequest() error")
+		require.NotNil(t, got, "dst.GetRequest() should return data")
+		require.Equal(t, int64(requestID), got.ID, "dst.GetRequest() should return correct request ID")
+		clientID = got.ClientID
+	})
+
+	t.Run("04 GetRequests by client", func(t *testing.T) {
+		dst := &Ctd{}
+		dst.Init(url, token)
+		got, total, err := dst.GetRequests(ctx, &GetRequestsParams{ClientID: clientID, Limit: 10})
+		require.NoError(t, err, "dst.GetRequests() error")
+		require.NotEmpty(t, got, "dst.GetRequests() should return data")
+		require.Greater(t, total, 0, "dst.GetRequests() should return total requests count")
+	})
+}
+
+func TestGetRequestsParams_Params(t *testing.T) {
+	date := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		params GetRequestsParams
+		want   string
+	}{
+		{
+			name:   "Empty",
+			params: GetRequestsParams{},
+			want:   "",
+		},
+		{
+			name:   "All filters",
+			params: GetRequestsParams{Limit: 10, Offset: 20, ClientID: 1, DialogID: 2, OperatorID: 3, StartDate: date, FinishDate: date.AddDate(0, 0, 1), Tags: []int64{4, 5}, Order: "desc"},
+			want:   "?limit=10&offset=20&client_id=1&dialog_id=2&operator_id=3&start_date=2026-01-02&finish_date=2026-01-03&tags=4,5&order=desc",
+		},
+		{
+			name:   "Invalid order",
+			params: GetRequestsParams{Order: "random"},
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.params.Params(), "GetRequestsParams.Params()")
+		})
+	}
+}
+
+func TestRequest_Metadata(t *testing.T) {
+	request := Request{}
+	err := json.Unmarshal([]byte(`{"id":1,"state":"closed","start":"2026-01-29T06:14:41 UTC","end":"2026-01-29T06:24:41 UTC","rating":5}`), &request)
+	require.NoError(t, err, "json.Unmarshal() error")
+	require.Equal(t, int64(5), request.GetRating(), "Request.GetRating()")
+	require.Equal(t, 10*time.Minute, request.GetDuration(), "Request.GetDuration()")
+	require.True(t, request.IsClosed(), "Request.IsClosed()")
+
+	request = Request{}
+	require.Equal(t, int64(-1), request.GetRating(), "Request.GetRating() for not rated request")
+	require.Zero(t, request.GetDuration(), "Request.GetDuration() for open request")
+}
+
+func TestCtd_AllRequests(t *testing.T) {
+	ctx := t.Context()
+
+	offsets := []int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		offsets = append(offsets, offset)
+
+		rows := []map[string]any{}
+		for i := offset; i < min(offset+200, 450); i++ {
+			rows = append(rows, map[string]any{"id": i + 1})
+		}
+		// The response has no total
+		json.NewEncoder(w).Encode(map[string]any{"status": "success", "data": rows})
+	}))
+	defer server.Close()
+
+	dst := &Ctd{}
+	dst.Init(server.URL, "token")
+
+	got, err := dst.AllRequests(ctx, nil)
+	require.NoError(t, err, "dst.AllRequests() error")
+	require.Len(t, got, 450, "all the pages should be loaded without the total")
+	require.Equal(t, []int{0, 200, 400}, offsets, "pages should be requested until a short one")
+	require.Equal(t, int64(450), got[449].ID, "requests should keep the order")
+
+	got, _, err = dst.GetRequests(ctx, nil)
+	require.NoError(t, err, "dst.GetRequests() error")
+	require.Len(t, got, 200, "nil params should request the first page")
+	require.Equal(t, 0, offsets[len(offsets)-1], "nil params should not set the offset")
+}
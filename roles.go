@@ -0,0 +1,196 @@
+package ctd
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Role is the role of an operator resolved from the Chat2Desk access rights.
+// Custom roles can be registered in a RoleResolver.
+type Role string
+
+const (
+	RoleOperator   Role = "operator"   // Regular operator
+	RoleSupervisor Role = "supervisor" // Supervisor
+	RoleAdmin      Role = "admin"      // Administrator
+	RoleDisabled   Role = "disabled"   // Disabled account
+	RoleDeleted    Role = "deleted"    // Deleted account
+)
+
+// Permission is a set of permissions granted to a role.
+type Permission uint32
+
+const (
+	PermissionChat            Permission = 1 << iota // Answer clients in own dialogs
+	PermissionTransfer                               // Transfer dialogs to other operators and groups
+	PermissionViewAllDialogs                         // View dialogs of other operators
+	PermissionViewReports                            // View statistics and reports
+	PermissionManageOperators                        // Manage operators and operator groups
+	PermissionManageSettings                         // Manage company settings, channels and webhooks
+
+	PermissionNone Permission = 0
+	PermissionAll  Permission = PermissionChat | PermissionTransfer | PermissionViewAllDialogs | PermissionViewReports | PermissionManageOperators | PermissionManageSettings
+)
+
+// Has reports whether all the specified permissions are granted.
+func (dst Permission) Has(permission Permission) bool {
+	return dst&permission == permission
+}
+
+// DefaultRoleResolver is the resolver used by Operator.GetLegacyRole and Operator.GetRole.
+// It can be configured with additional names, access rights and roles at startup.
+var DefaultRoleResolver = NewRoleResolver()
+
+type roleName struct {
+	name string
+	role Role
+}
+
+// RoleResolver resolves the role of an operator from its status, access right and legacy role.
+// Access rights are matched by ID first and then by name. A name matches if the access right name
+// equals it or contains it in brackets (e.g. "IT department (admin)"). Names are case-insensitive
+// and are checked in the order they were added.
+type RoleResolver struct {
+	mu           sync.RWMutex
+	names        []roleName
+	accessRights map[int64]Role
+	permissions  map[Role]Permission
+}
+
+// NewRoleResolver creates a new RoleResolver with the built-in roles and
+// the localized Chat2Desk names of the supervisor and administrator access rights.
+//
+// Returns:
+//   - A pointer to a RoleResolver.
+func NewRoleResolver() *RoleResolver {
+	resolver := &RoleResolver{
+		accessRights: map[int64]Role{},
+		permissions: map[Role]Permission{
+			RoleOperator:   PermissionChat | PermissionTransfer,
+			RoleSupervisor: PermissionChat | PermissionTransfer | PermissionViewAllDialogs | PermissionViewReports | PermissionManageOperators,
+			RoleAdmin:      PermissionAll,
+			RoleDisabled:   PermissionNone,
+			RoleDeleted:    PermissionNone,
+		},
+	}
+
+	resolver.AddNames(RoleSupervisor, "Супервайзер", "Supervisor", "Amir", "O supervisor", "El supervisor", "Supervayzer", "Supervizor", "Le superviseur")
+	resolver.AddNames(RoleAdmin, "Администратор", "Administrator", "Admin", "O administrador", "El administrador", "Administrateur", "Адміністратор")
+
+	return resolver
+}
+
+// AddNames adds access right names that resolve to the specified role.
+//
+// Parameters:
+//   - role: The role to resolve to.
+//   - names: The access right names.
+func (dst *RoleResolver) AddNames(role Role, names ...string) {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	for _, name := range names {
+		dst.names = append(dst.names, roleName{name: strings.ToLower(name), role: role})
+	}
+}
+
+// AddAccessRight maps an access right ID to the specified role.
+// Access right IDs take precedence over access right names.
+//
+// Parameters:
+//   - id: The access right ID.
+//   - role: The role to resolve to.
+func (dst *RoleResolver) AddAccessRight(id int64, role Role) {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	dst.accessRights[id] = role
+}
+
+// AddRole registers a custom role or overrides the permissions of an existing one.
+//
+// Parameters:
+//   - role: The role.
+//   - permissions: The permissions granted to the role.
+func (dst *RoleResolver) AddRole(role Role, permissions Permission) {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	dst.permissions[role] = permissions
+}
+
+// Permissions returns the permissions granted to the role.
+// Unknown roles get the permissions of a regular operator.
+//
+// Parameters:
+//   - role: The role.
+//
+// Returns:
+//   - The permissions granted to the role.
+func (dst *RoleResolver) Permissions(role Role) Permission {
+	dst.mu.RLock()
+	defer dst.mu.RUnlock()
+
+	if permissions, ok := dst.permissions[role]; ok {
+		return permissions
+	}
+
+	return dst.permissions[RoleOperator]
+}
+
+// Resolve returns the role of the operator.
+//
+// Parameters:
+//   - operator: The operator.
+//
+// Returns:
+//   - The resolved role (RoleOperator if nothing matches).
+func (dst *RoleResolver) Resolve(operator *Operator) Role {
+	// Check new API version first
+	if operator.Status != "" {
+		str := strings.ToLower(operator.Status)
+		switch Role(str) {
+		case RoleAdmin, RoleDisabled, RoleDeleted:
+			return Role(str)
+		}
+
+		dst.mu.RLock()
+		defer dst.mu.RUnlock()
+
+		if role, ok := dst.accessRights[operator.AccessRightID]; ok && operator.AccessRightID != 0 {
+			return role
+		}
+
+		str = strings.ToLower(operator.AccessRightName)
+		for _, item := range dst.names {
+			if str == item.name || strings.Contains(str, fmt.Sprintf("(%s)", item.name)) {
+				return item.role
+			}
+		}
+		return RoleOperator
+	}
+
+	// Fallback to old API version
+	if operator.Role != "" {
+		return Role(strings.ToLower(operator.Role))
+	}
+
+	return RoleOperator
+}
+
+// GetRole returns the role of the operator resolved by the DefaultRoleResolver.
+//
+// Returns:
+//   - The role of the operator.
+func (dst *Operator) GetRole() Role {
+	return DefaultRoleResolver.Resolve(dst)
+}
+
+// GetPermissions returns the permissions of the operator resolved by the DefaultRoleResolver.
+//
+// Returns:
+//   - The permissions granted to the operator.
+func (dst *Operator) GetPermissions() Permission {
+	return DefaultRoleResolver.Permissions(dst.GetRole())
+}
@@ -0,0 +1,78 @@
+package ctd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleResolver_Resolve(t *testing.T) {
+	resolver := NewRoleResolver()
+	resolver.AddNames(RoleSupervisor, "Старший смены")
+	resolver.AddAccessRight(100, RoleAdmin)
+	resolver.AddRole("teamlead", PermissionChat|PermissionTransfer|PermissionViewReports)
+	resolver.AddNames("teamlead", "Team lead")
+
+	tests := []struct {
+		name     string
+		operator Operator
+		want     Role
+	}{
+		{
+			name:     "Built-in supervisor name",
+			operator: Operator{Status: "enabled", AccessRightName: "Supervisor"},
+			want:     RoleSupervisor,
+		},
+		{
+			name:     "Additional supervisor name in brackets",
+			operator: Operator{Status: "enabled", AccessRightName: "Продажи (старший смены)"},
+			want:     RoleSupervisor,
+		},
+		{
+			name:     "Access right ID",
+			operator: Operator{Status: "enabled", AccessRightID: 100, AccessRightName: "Directors"},
+			want:     RoleAdmin,
+		},
+		{
+			name:     "Custom role",
+			operator: Operator{Status: "enabled", AccessRightName: "Team Lead"},
+			want:     "teamlead",
+		},
+		{
+			name:     "Unknown access right",
+			operator: Operator{Status: "enabled", AccessRightName: "Directors"},
+			want:     RoleOperator,
+		},
+		{
+			name:     "Disabled by status",
+			operator: Operator{Status: "disabled", AccessRightID: 100},
+			want:     RoleDisabled,
+		},
+		{
+			name:     "Legacy role",
+			operator: Operator{Role: "Supervisor"},
+			want:     RoleSupervisor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, resolver.Resolve(&tt.operator), "RoleResolver.Resolve()")
+		})
+	}
+}
+
+func TestRoleResolver_Permissions(t *testing.T) {
+	resolver := NewRoleResolver()
+	resolver.AddRole("teamlead", PermissionChat|PermissionViewReports)
+
+	require.True(t, resolver.Permissions(RoleAdmin).Has(PermissionManageSettings), "admin should manage settings")
+	require.True(t, resolver.Permissions(RoleSupervisor).Has(PermissionViewReports|PermissionManageOperators), "supervisor should view reports and manage operators")
+	require.False(t, resolver.Permissions(RoleSupervisor).Has(PermissionManageSettings), "supervisor should not manage settings")
+	require.False(t, resolver.Permissions(RoleOperator).Has(PermissionViewReports), "operator should not view reports")
+	require.Equal(t, PermissionNone, resolver.Permissions(RoleDeleted), "deleted account should have no permissions")
+	require.True(t, resolver.Permissions("teamlead").Has(PermissionViewReports), "custom role permissions")
+	require.Equal(t, resolver.Permissions(RoleOperator), resolver.Permissions("unknown"), "unknown role should get operator permissions")
+
+	operator := Operator{Status: "admin"}
+	require.Equal(t, PermissionAll, operator.GetPermissions(), "Operator.GetPermissions()")
+}
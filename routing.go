@@ -0,0 +1,208 @@
+package ctd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+)
+
+var (
+	ErrorNoOperatorAvailable = fmt.Errorf("no operator available")
+)
+
+const (
+	RoutingLeastBusy  = "least_busy"  // Operator with the fewest opened dialogs
+	RoutingRoundRobin = "round_robin" // Operators of the group in turn
+	RoutingSkills     = "skills"      // Least busy operator having all the required skills (tags)
+	RoutingSticky     = "sticky"      // Previous operator of the dialog, or the least busy one
+)
+
+// RoutingRequest describes a message to be routed to an operator of a group.
+type RoutingRequest struct {
+	MessageID int64   // MessageID: ID of the message to transfer
+	GroupID   int64   // GroupID: ID of the operator group to pick the operator from
+	DialogID  int64   // DialogID: Optional dialog ID, used by the sticky strategy to find the previous operator
+	Skills    []int64 // Skills: Optional tag IDs the operator must have (skills strategy)
+	Force     bool    // Force: Force flag used when falling back to the group transfer
+}
+
+// RoutingResult describes where a message was routed to.
+type RoutingResult struct {
+	OperatorID int64 // OperatorID: ID of the operator the message was transferred to (0 on fallback)
+	GroupID    int64 // GroupID: ID of the group
+	Fallback   bool  // Fallback: Whether the message was transferred to the group instead of an operator
+}
+
+// Router picks an operator of a group based on the operators' load and transfers messages to them.
+// Only online operators of the group are considered. If no operator is available,
+// or the transfer to the operator fails, the message is transferred to the group.
+type Router struct {
+	Strategy         string            // Strategy: Routing strategy (default: least_busy)
+	MaxOpenedDialogs int64             // MaxOpenedDialogs: Operators with this number of opened dialogs or more are skipped (0 - no limit)
+	StatusIDs        []uint8           // StatusIDs: Allowed operator statuses (empty - any status)
+	Skills           map[int64][]int64 // Skills: Tag IDs describing the skills of every operator (skills strategy)
+
+	ctd  *Ctd
+	mu   sync.Mutex
+	next map[int64]int
+}
+
+// NewRouter creates a new Router with the specified strategy.
+//
+// Parameters:
+//   - strategy: The routing strategy ('least_busy', 'round_robin', 'skills' or 'sticky').
+//
+// Returns:
+//   - A pointer to a Router.
+func (dst *Ctd) NewRouter(strategy string) *Router {
+	return &Router{
+		Strategy: strategy,
+		Skills:   map[int64][]int64{},
+		ctd:      dst,
+		next:     map[int64]int{},
+	}
+}
+
+// Pick selects an operator of the group for the request without transferring the message.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - request: The routing request.
+//
+// Returns:
+//   - A pointer to the selected Operator.
+//   - ErrorNoOperatorAvailable if no operator matches, or another error if the request fails.
+func (dst *Router) Pick(ctx context.Context, request *RoutingRequest) (*Operator, error) {
+	group, err := dst.ctd.GetOperatorGroup(ctx, request.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	operators, err := dst.ctd.AllOperators(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := int64(0)
+	if dst.Strategy == RoutingSticky && request.DialogID > 0 {
+		dialog, err := dst.ctd.GetDialog(ctx, request.DialogID)
+		if err != nil {
+			return nil, err
+		}
+		previous = dialog.OperatorID
+	}
+
+	return dst.pick(dst.candidates(group, operators), request, previous)
+}
+
+// Route selects an operator of the group and transfers the message to them.
+// If no operator is available or the transfer fails, the message is transferred to the group.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - request: The routing request.
+//
+// Returns:
+//   - A pointer to a RoutingResult describing where the message was transferred to.
+//   - An error if the transfer to the group fails too.
+func (dst *Router) Route(ctx context.Context, request *RoutingRequest) (*RoutingResult, error) {
+	operator, err := dst.Pick(ctx, request)
+	if err == nil {
+		err = dst.ctd.TransferToOperator(ctx, request.MessageID, operator.ID)
+		if err == nil {
+			return &RoutingResult{OperatorID: operator.ID, GroupID: request.GroupID}, nil
+		}
+	}
+
+	switch err {
+	case ErrorInvalidMesssageID, ErrorInvalidOperatorGroupID, ErrorInvalidToken:
+		return nil, err
+	}
+	dst.ctd.Error(ctx, "Failed to route message %d to operator, falling back to group %d: %v", request.MessageID, request.GroupID, err)
+
+	if err := dst.ctd.TransferToGroup(ctx, request.MessageID, request.GroupID, request.Force); err != nil {
+		return nil, err
+	}
+
+	return &RoutingResult{GroupID: request.GroupID, Fallback: true}, nil
+}
+
+// candidates returns the online operators of the group allowed by the router settings sorted by ID.
+func (dst *Router) candidates(group *OperatorGroup, operators []Operator) []Operator {
+	result := []Operator{}
+	for _, operator := range operators {
+		if !slices.Contains(group.Operators, operator.ID) {
+			continue
+		}
+		if operator.Online != 1 {
+			continue
+		}
+		if len(dst.StatusIDs) > 0 && !slices.Contains(dst.StatusIDs, operator.StatusID) {
+			continue
+		}
+		if dst.MaxOpenedDialogs > 0 && operator.OpenedDialogs >= dst.MaxOpenedDialogs {
+			continue
+		}
+		result = append(result, operator)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result
+}
+
+// pick selects an operator from the candidates according to the strategy.
+func (dst *Router) pick(candidates []Operator, request *RoutingRequest, previous int64) (*Operator, error) {
+	switch dst.Strategy {
+	case RoutingRoundRobin:
+		if len(candidates) == 0 {
+			return nil, ErrorNoOperatorAvailable
+		}
+		dst.mu.Lock()
+		index := dst.next[request.GroupID] % len(candidates)
+		dst.next[request.GroupID] = index + 1
+		dst.mu.Unlock()
+		return &candidates[index], nil
+	case RoutingSkills:
+		skilled := []Operator{}
+		for _, operator := range candidates {
+			if hasAllIDs(dst.Skills[operator.ID], request.Skills) {
+				skilled = append(skilled, operator)
+			}
+		}
+		candidates = skilled
+	case RoutingSticky:
+		for i := range candidates {
+			if previous != 0 && candidates[i].ID == previous {
+				return &candidates[i], nil
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, ErrorNoOperatorAvailable
+	}
+
+	best := 0
+	for i := range candidates {
+		if candidates[i].OpenedDialogs < candidates[best].OpenedDialogs {
+			best = i
+		}
+	}
+
+	return &candidates[best], nil
+}
+
+// hasAllIDs reports whether all the required IDs are present in the list.
+func hasAllIDs(ids, required []int64) bool {
+	for _, id := range required {
+		if !slices.Contains(ids, id) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package ctd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_Pick(t *testing.T) {
+	group := &OperatorGroup{ID: 1, Operators: []int64{1, 2, 3, 4, 5}}
+	operators := []Operator{
+		{ID: 1, Online: 1, StatusID: 1, OpenedDialogs: 5},
+		{ID: 2, Online: 1, StatusID: 1, OpenedDialogs: 2},
+		{ID: 3, Online: 0, StatusID: 1, OpenedDialogs: 0},
+		{ID: 4, Online: 1, StatusID: 2, OpenedDialogs: 1},
+		{ID: 5, Online: 1, StatusID: 1, OpenedDialogs: 3},
+		{ID: 6, Online: 1, StatusID: 1, OpenedDialogs: 0},
+	}
+	dst := &Ctd{}
+
+	t.Run("Least busy", func(t *testing.T) {
+		router := dst.NewRouter(RoutingLeastBusy)
+		router.StatusIDs = []uint8{1}
+		got, err := router.pick(router.candidates(group, operators), &RoutingRequest{GroupID: 1}, 0)
+		require.NoError(t, err, "Router.pick() error")
+		require.Equal(t, int64(2), got.ID, "Router.pick() should return the least busy operator")
+	})
+
+	t.Run("Max opened dialogs", func(t *testing.T) {
+		router := dst.NewRouter(RoutingLeastBusy)
+		router.MaxOpenedDialogs = 1
+		_, err := router.pick(router.candidates(group, operators), &RoutingRequest{GroupID: 1}, 0)
+		require.ErrorIs(t, err, ErrorNoOperatorAvailable, "Router.pick() error")
+	})
+
+	t.Run("Round robin", func(t *testing.T) {
+		router := dst.NewRouter(RoutingRoundRobin)
+		candidates := router.candidates(group, operators)
+		got := []int64{}
+		for range 5 {
+			operator, err := router.pick(candidates, &RoutingRequest{GroupID: 1}, 0)
+			require.NoError(t, err, "Router.pick() error")
+			got = append(got, operator.ID)
+		}
+		require.Equal(t, []int64{1, 2, 4, 5, 1}, got, "Router.pick() should return operators in turn")
+	})
+
+	t.Run("Skills", func(t *testing.T) {
+		router := dst.NewRouter(RoutingSkills)
+		router.Skills = map[int64][]int64{1: {10, 20}, 2: {10}, 5: {10, 20, 30}}
+		got, err := router.pick(router.candidates(group, operators), &RoutingRequest{GroupID: 1, Skills: []int64{10, 20}}, 0)
+		require.NoError(t, err, "Router.pick() error")
+		require.Equal(t, int64(5), got.ID, "Router.pick() should return the least busy skilled operator")
+
+		_, err = router.pick(router.candidates(group, operators), &RoutingRequest{GroupID: 1, Skills: []int64{40}}, 0)
+		require.ErrorIs(t, err, ErrorNoOperatorAvailable, "Router.pick() error")
+	})
+
+	t.Run("Sticky", func(t *testing.T) {
+		router := dst.NewRouter(RoutingSticky)
+		got, err := router.pick(router.candidates(group, operators), &RoutingRequest{GroupID: 1}, 5)
+		require.NoError(t, err, "Router.pick() error")
+		require.Equal(t, int64(5), got.ID, "Router.pick() should return the previous operator")
+
+		got, err = router.pick(router.candidates(group, operators), &RoutingRequest{GroupID: 1}, 3)
+		require.NoError(t, err, "Router.pick() error")
+		require.Equal(t, int64(4), got.ID, "Router.pick() should return the least busy operator if the previous one is offline")
+	})
+}
@@ -0,0 +1,523 @@
+package ctd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrorUnknownStep = fmt.Errorf("unknown scenario step")
+)
+
+// ConversationState is the state of a client's conversation with a scenario.
+type ConversationState struct {
+	ClientID  int64             `json:"client_id"`  // ClientID: ID of the client
+	Step      string            `json:"step"`       // Step: Name of the current step
+	Data      map[string]string `json:"data"`       // Data: Values collected from the client
+	UpdatedAt time.Time         `json:"updated_at"` // UpdatedAt: Time when the client entered the current step
+	ChannelID int64             `json:"channel_id"` // ChannelID: Channel of the last message of the client
+	Transport string            `json:"transport"`  // Transport: Transport of the last message of the client
+	MessageID int64             `json:"message_id"` // MessageID: ID of the last message of the client, used to hand the dialog off
+}
+
+// StateStore stores the conversation states of the clients.
+// Implementations must be safe for concurrent use.
+type StateStore interface {
+	// Load returns the state of the client, or nil if the client has no active conversation.
+	Load(ctx context.Context, client_id int64) (*ConversationState, error)
+	// Save stores the state of the client.
+	Save(ctx context.Context, state *ConversationState) error
+	// Delete removes the state of the client.
+	Delete(ctx context.Context, client_id int64) error
+	// List returns the states of all the active conversations.
+	List(ctx context.Context) ([]*ConversationState, error)
+}
+
+// MemoryStateStore is an in-memory StateStore.
+type MemoryStateStore struct {
+	mu     sync.Mutex
+	states map[int64]ConversationState
+}
+
+// NewMemoryStateStore creates a new empty MemoryStateStore.
+//
+// Returns:
+//   - A pointer to a MemoryStateStore.
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{
+		states: map[int64]ConversationState{},
+	}
+}
+
+// Load returns a copy of the state of the client, or nil if the client has no active conversation.
+func (dst *MemoryStateStore) Load(ctx context.Context, client_id int64) (*ConversationState, error) {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	state, ok := dst.states[client_id]
+	if !ok {
+		return nil, nil
+	}
+
+	return copyConversationState(state), nil
+}
+
+// Save stores a copy of the state of the client.
+func (dst *MemoryStateStore) Save(ctx context.Context, state *ConversationState) error {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	dst.states[state.ClientID] = *state
+	return nil
+}
+
+// Delete removes the state of the client.
+func (dst *MemoryStateStore) Delete(ctx context.Context, client_id int64) error {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	delete(dst.states, client_id)
+	return nil
+}
+
+// List returns copies of the states of all the active conversations.
+func (dst *MemoryStateStore) List(ctx context.Context) ([]*ConversationState, error) {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	result := make([]*ConversationState, 0, len(dst.states))
+	for _, state := range dst.states {
+		result = append(result, copyConversationState(state))
+	}
+	return result, nil
+}
+
+// copyConversationState returns a copy of the state that doesn't share the data map.
+func copyConversationState(state ConversationState) *ConversationState {
+	data := make(map[string]string, len(state.Data))
+	for key, value := range state.Data {
+		data[key] = value
+	}
+	state.Data = data
+	return &state
+}
+
+// ScenarioInput is an inbound message processed by a scenario step.
+type ScenarioInput struct {
+	Notification *WebhookNotification // Notification: Inbound webhook notification
+	Text         string               // Text: Trimmed text of the message or the label of the pressed button
+	State        *ConversationState   // State: Conversation state of the client
+}
+
+// ScenarioMatcher reports whether the input is accepted by a transition.
+type ScenarioMatcher func(input *ScenarioInput) bool
+
+// MatchAny accepts any input.
+func MatchAny() ScenarioMatcher {
+	return func(input *ScenarioInput) bool {
+		return true
+	}
+}
+
+// MatchText accepts the input equal to one of the values, case-insensitively.
+// Keyboard and inline button replies arrive as the button labels, so it also matches buttons.
+func MatchText(values ...string) ScenarioMatcher {
+	return func(input *ScenarioInput) bool {
+		for _, value := range values {
+			if strings.EqualFold(strings.TrimSpace(value), input.Text) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchRegexp accepts the input matching the regular expression.
+// It panics if the expression can't be compiled, like regexp.MustCompile.
+func MatchRegexp(pattern string) ScenarioMatcher {
+	re := regexp.MustCompile(pattern)
+	return func(input *ScenarioInput) bool {
+		return re.MatchString(input.Text)
+	}
+}
+
+// MatchPhone accepts the input containing a phone number.
+func MatchPhone() ScenarioMatcher {
+	detector := PhoneDetector()
+	return func(input *ScenarioInput) bool {
+		for _, value := range detector.Pattern.FindAllString(input.Text, -1) {
+			if detector.Validate == nil || detector.Validate(value) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ScenarioTransition moves the conversation to the next step when the input matches.
+type ScenarioTransition struct {
+	Match  ScenarioMatcher                                       // Match: Input matcher (nil - any input)
+	Next   string                                                // Next: Name of the next step ("" - finish the scenario)
+	Save   string                                                // Save: Key the input is saved under in the conversation data (optional)
+	Action func(ctx context.Context, input *ScenarioInput) error // Action: Optional action called before moving to the next step
+}
+
+// ScenarioStep is a state of the scenario.
+// On entering the step its message is sent to the client. Then the next inbound message
+// is checked against the transitions in order; if none matches, the fallback message is sent
+// and the client stays at the step.
+type ScenarioStep struct {
+	Name        string               // Name: Unique name of the step
+	Message     *MessagePayload      // Message: Message sent on entering the step; {key} placeholders are replaced with the conversation data
+	Transitions []ScenarioTransition // Transitions: Transitions checked in order
+	Fallback    *MessagePayload      // Fallback: Message sent when no transition matches (nil - the step message is repeated)
+	Timeout     time.Duration        // Timeout: Maximum time to wait for the client's answer (0 - no limit)
+	TimeoutStep string               // TimeoutStep: Step entered after the timeout ("" - the conversation is restarted by the next message)
+	HandOff     int64                // HandOff: Operator group the dialog is transferred to on entering the step; the scenario finishes
+}
+
+// Scenario is a finite-state machine driving the conversations of the clients.
+// It processes the inbound messages (the 'inbox' webhooks): a client without a conversation
+// enters the start step, other clients move along the transitions of their current step.
+// Timeouts are checked when the next message of the client arrives and by Sweep, which enters
+// the timeout steps without waiting for the client, e.g. to hand the dialog off to an operator.
+// Handle matches the callback of WebhookDispatcher. Handle and Sweep process the same client one at a time
+// within the process; scenarios running in several processes must share the clients through a dispatcher.
+type Scenario struct {
+	Start string                   // Start: Name of the start step
+	Steps map[string]*ScenarioStep // Steps: Steps by name
+	Store StateStore               // Store: Conversation state store
+
+	ctd   *Ctd
+	mu    sync.Mutex
+	locks map[int64]*scenarioLock
+}
+
+// scenarioLock serializes the processing of a client.
+type scenarioLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// NewScenario creates a new Scenario and checks that all the referenced steps exist.
+//
+// Parameters:
+//   - store: The conversation state store (nil - MemoryStateStore).
+//   - steps: The steps of the scenario; the first one is the start step.
+//
+// Returns:
+//   - A pointer to a Scenario.
+//   - ErrorUnknownStep if a transition refers to an unknown step, or ErrorInvalidParameters if there are no steps.
+func (dst *Ctd) NewScenario(store StateStore, steps ...*ScenarioStep) (*Scenario, error) {
+	if len(steps) == 0 {
+		return nil, ErrorInvalidParameters
+	}
+	if store == nil {
+		store = NewMemoryStateStore()
+	}
+
+	scenario := &Scenario{
+		Start: steps[0].Name,
+		Steps: make(map[string]*ScenarioStep, len(steps)),
+		Store: store,
+		ctd:   dst,
+	}
+	for _, step := range steps {
+		if _, ok := scenario.Steps[step.Name]; ok || step.Name == "" {
+			return nil, ErrorInvalidParameters
+		}
+		scenario.Steps[step.Name] = step
+	}
+
+	for _, step := range steps {
+		if step.TimeoutStep != "" && scenario.Steps[step.TimeoutStep] == nil {
+			return nil, fmt.Errorf("%w: %s", ErrorUnknownStep, step.TimeoutStep)
+		}
+		for _, transition := range step.Transitions {
+			if transition.Next != "" && scenario.Steps[transition.Next] == nil {
+				return nil, fmt.Errorf("%w: %s", ErrorUnknownStep, transition.Next)
+			}
+		}
+	}
+
+	return scenario, nil
+}
+
+// Handle processes a webhook notification. Notifications other than inbound client messages are ignored.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - notification: The webhook notification.
+//
+// Returns:
+//   - An error if the state store, the action or the API request fails.
+func (dst *Scenario) Handle(ctx context.Context, notification *WebhookNotification) error {
+	if notification.HookType != "inbox" || notification.ClientID == 0 {
+		return nil
+	}
+	if notification.Type != "" && notification.Type != "from_client" {
+		return nil
+	}
+
+	unlock := dst.lock(notification.ClientID)
+	defer unlock()
+
+	state, err := dst.Store.Load(ctx, notification.ClientID)
+	if err != nil {
+		return err
+	}
+
+	if state == nil {
+		state = &ConversationState{ClientID: notification.ClientID, Data: map[string]string{}}
+	}
+	if state.Data == nil {
+		state.Data = map[string]string{}
+	}
+	state.ChannelID = notification.ChannelID
+	state.Transport = notification.Transport
+	state.MessageID = notification.MessageID
+	if state.Step == "" {
+		return dst.enter(ctx, notification, state, dst.Start)
+	}
+
+	step := dst.Steps[state.Step]
+	if step == nil {
+		dst.ctd.Error(ctx, "Unknown scenario step %q of client %d, restarting", state.Step, state.ClientID)
+		return dst.enter(ctx, notification, state, dst.Start)
+	}
+
+	if dst.expired(state) {
+		next := step.TimeoutStep
+		if next == "" {
+			next = dst.Start
+		}
+		return dst.enter(ctx, notification, state, next)
+	}
+
+	input := &ScenarioInput{
+		Notification: notification,
+		Text:         strings.TrimSpace(notification.Text),
+		State:        state,
+	}
+
+	for _, transition := range step.Transitions {
+		if transition.Match != nil && !transition.Match(input) {
+			continue
+		}
+
+		if transition.Save != "" {
+			state.Data[transition.Save] = input.Text
+		}
+		if transition.Action != nil {
+			if err := transition.Action(ctx, input); err != nil {
+				return err
+			}
+		}
+
+		if transition.Next == "" {
+			return dst.Store.Delete(ctx, state.ClientID)
+		}
+		return dst.enter(ctx, notification, state, transition.Next)
+	}
+
+	message := step.Fallback
+	if message == nil {
+		message = step.Message
+	}
+	return dst.send(ctx, notification, state, message)
+}
+
+// Sweep moves the clients whose answer timed out to the timeout steps of their current steps.
+// The conversations of the steps without a timeout step are finished, so the next message starts over.
+// Sweep should be called periodically, e.g. with RunSweep.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//
+// Returns:
+//   - The number of the timed out conversations.
+//   - An error if the state store fails, or the joined errors of the conversations that can't be moved.
+func (dst *Scenario) Sweep(ctx context.Context) (int, error) {
+	states, err := dst.Store.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	errs := []error{}
+	for _, state := range states {
+		if !dst.expired(state) {
+			continue
+		}
+
+		ok, err := dst.timeout(ctx, state)
+		if err != nil {
+			dst.ctd.Error(ctx, "Failed to time out scenario step %q of client %d: %v", state.Step, state.ClientID, err)
+			errs = append(errs, err)
+		}
+		if ok {
+			count++
+		}
+	}
+
+	return count, errors.Join(errs...)
+}
+
+// timeout moves the client to the timeout step of the listed state, unless the client
+// has answered since the state was listed. It reports whether the conversation timed out.
+func (dst *Scenario) timeout(ctx context.Context, listed *ConversationState) (bool, error) {
+	unlock := dst.lock(listed.ClientID)
+	defer unlock()
+
+	state, err := dst.Store.Load(ctx, listed.ClientID)
+	if err != nil {
+		return false, err
+	}
+	if state == nil || state.Step != listed.Step || !state.UpdatedAt.Equal(listed.UpdatedAt) || !dst.expired(state) {
+		return false, nil
+	}
+
+	step := dst.Steps[state.Step]
+	if step.TimeoutStep == "" {
+		return true, dst.Store.Delete(ctx, state.ClientID)
+	}
+
+	if state.Data == nil {
+		state.Data = map[string]string{}
+	}
+	notification := &WebhookNotification{
+		HookType:  "inbox",
+		ClientID:  state.ClientID,
+		ChannelID: state.ChannelID,
+		Transport: state.Transport,
+		MessageID: state.MessageID,
+	}
+	return true, dst.enter(ctx, notification, state, step.TimeoutStep)
+}
+
+// expired reports whether the client didn't answer within the timeout of the current step.
+func (dst *Scenario) expired(state *ConversationState) bool {
+	step := dst.Steps[state.Step]
+	return step != nil && step.Timeout > 0 && time.Since(state.UpdatedAt) > step.Timeout
+}
+
+// lock locks the processing of the client and returns the function unlocking it.
+func (dst *Scenario) lock(client_id int64) func() {
+	dst.mu.Lock()
+	if dst.locks == nil {
+		dst.locks = map[int64]*scenarioLock{}
+	}
+	item, ok := dst.locks[client_id]
+	if !ok {
+		item = &scenarioLock{}
+		dst.locks[client_id] = item
+	}
+	item.refs++
+	dst.mu.Unlock()
+
+	item.mu.Lock()
+
+	return func() {
+		item.mu.Unlock()
+
+		dst.mu.Lock()
+		item.refs--
+		if item.refs == 0 {
+			delete(dst.locks, client_id)
+		}
+		dst.mu.Unlock()
+	}
+}
+
+// RunSweep calls Sweep with the interval until the context is canceled.
+// Sweep errors are logged and sweeping continues.
+//
+// Parameters:
+//   - ctx: The context controlling the sweeper lifetime.
+//   - interval: The sweep interval (default: 1 minute).
+//
+// Returns:
+//   - The context error when the context is canceled.
+func (dst *Scenario) RunSweep(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		if _, err := dst.Sweep(ctx); err != nil && ctx.Err() == nil {
+			dst.ctd.Error(ctx, "Failed to sweep scenario timeouts: %v", err)
+		}
+	}
+}
+
+// Reset finishes the conversation of the client.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - client_id: The ID of the client.
+//
+// Returns:
+//   - An error if the state store fails.
+func (dst *Scenario) Reset(ctx context.Context, client_id int64) error {
+	return dst.Store.Delete(ctx, client_id)
+}
+
+// enter moves the client to the step, sends its message and hands the dialog off if required.
+func (dst *Scenario) enter(ctx context.Context, notification *WebhookNotification, state *ConversationState, name string) error {
+	step := dst.Steps[name]
+	if step == nil {
+		return fmt.Errorf("%w: %s", ErrorUnknownStep, name)
+	}
+
+	state.Step = name
+	state.UpdatedAt = time.Now()
+
+	if err := dst.send(ctx, notification, state, step.Message); err != nil {
+		return err
+	}
+
+	if step.HandOff != 0 {
+		if err := dst.ctd.TransferToGroup(ctx, notification.MessageID, step.HandOff, false); err != nil {
+			return err
+		}
+		return dst.Store.Delete(ctx, state.ClientID)
+	}
+
+	return dst.Store.Save(ctx, state)
+}
+
+// send sends a copy of the message to the client replacing the placeholders with the conversation data.
+func (dst *Scenario) send(ctx context.Context, notification *WebhookNotification, state *ConversationState, message *MessagePayload) error {
+	if message == nil {
+		return nil
+	}
+
+	payload := *message
+	payload.ClientID = state.ClientID
+	if payload.ChannelID == 0 {
+		payload.ChannelID = notification.ChannelID
+	}
+	if payload.Transport == "" {
+		payload.Transport = notification.Transport
+	}
+	for key, value := range state.Data {
+		payload.Text = strings.ReplaceAll(payload.Text, "{"+key+"}", value)
+	}
+
+	_, err := dst.ctd.SendMessage(ctx, &payload)
+	return err
+}
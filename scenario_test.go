@@ -0,0 +1,180 @@
+package ctd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMessagesServer records the sent messages and the transfers to groups.
+type fakeMessagesServer struct {
+	mu        sync.Mutex
+	messages  []MessagePayload
+	transfers []string
+}
+
+func (dst *fakeMessagesServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/v1/messages":
+		message := MessagePayload{}
+		json.NewDecoder(r.Body).Decode(&message)
+		dst.messages = append(dst.messages, message)
+		w.Write([]byte(`{"status":"success","data":{"message_id":1}}`))
+	case r.Method == "GET":
+		dst.transfers = append(dst.transfers, r.URL.String())
+		w.Write([]byte(`{"status":"success"}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (dst *fakeMessagesServer) texts() []string {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	texts := []string{}
+	for _, message := range dst.messages {
+		texts = append(texts, message.Text)
+	}
+	return texts
+}
+
+func TestScenario(t *testing.T) {
+	ctx := context.Background()
+
+	fake := &fakeMessagesServer{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	dst := &Ctd{}
+	dst.Init(server.URL, "token")
+
+	_, err := dst.NewScenario(nil, &ScenarioStep{Name: "menu", Transitions: []ScenarioTransition{{Next: "missing"}}})
+	require.ErrorIs(t, err, ErrorUnknownStep, "dst.NewScenario() should check the steps")
+
+	store := NewMemoryStateStore()
+	scenario, err := dst.NewScenario(store,
+		&ScenarioStep{
+			Name: "menu",
+			Message: &MessagePayload{Text: "Choose", Keyboard: &MessageButtons{Buttons: []MessageButton{
+				{Type: "reply", Text: "Call me"}, {Type: "reply", Text: "Operator"},
+			}}},
+			Transitions: []ScenarioTransition{
+				{Match: MatchText("call me"), Next: "phone"},
+				{Match: MatchText("operator"), Next: "handoff"},
+			},
+			Fallback: &MessagePayload{Text: "Please use the buttons"},
+		},
+		&ScenarioStep{
+			Name:        "phone",
+			Message:     &MessagePayload{Text: "Your phone?"},
+			Transitions: []ScenarioTransition{{Match: MatchPhone(), Save: "phone", Next: "done"}},
+			Timeout:     time.Hour,
+		},
+		&ScenarioStep{
+			Name:        "done",
+			Message:     &MessagePayload{Text: "We will call {phone}"},
+			Transitions: []ScenarioTransition{{Next: ""}},
+		},
+		&ScenarioStep{
+			Name:    "handoff",
+			Message: &MessagePayload{Text: "Connecting to an operator"},
+			HandOff: 7,
+		},
+	)
+	require.NoError(t, err, "dst.NewScenario() error")
+
+	inbox := func(client int64, text string) {
+		t.Helper()
+		err := scenario.Handle(ctx, &WebhookNotification{HookType: "inbox", Type: "from_client", MessageID: 100 + client, ClientID: client, ChannelID: 5, Text: text})
+		require.NoError(t, err, "scenario.Handle() error")
+	}
+
+	inbox(1, "hi")
+	inbox(1, "what?")
+	inbox(1, "Call me")
+	inbox(1, "+7 (999) 123-45-67")
+	require.Equal(t, []string{"Choose", "Please use the buttons", "Your phone?", "We will call +7 (999) 123-45-67"}, fake.texts(), "sent messages")
+	require.Equal(t, int64(1), fake.messages[0].ClientID, "messages should be sent to the client")
+	require.Equal(t, int64(5), fake.messages[0].ChannelID, "messages should be sent to the channel of the client")
+	require.Len(t, fake.messages[0].Keyboard.Buttons, 2, "keyboard should be sent")
+
+	state, err := store.Load(ctx, 1)
+	require.NoError(t, err, "store.Load() error")
+	require.Equal(t, "done", state.Step, "client should be at the last step")
+
+	inbox(1, "thanks")
+	state, err = store.Load(ctx, 1)
+	require.NoError(t, err, "store.Load() error")
+	require.Nil(t, state, "finished conversation should be removed")
+
+	t.Run("Hand-off", func(t *testing.T) {
+		inbox(2, "hi")
+		inbox(2, "OPERATOR")
+		require.Equal(t, []string{"/v1/messages/102/transfer_to_group?group_id=7&force=false"}, fake.transfers, "dialog should be transferred to the group")
+		state, err := store.Load(ctx, 2)
+		require.NoError(t, err, "store.Load() error")
+		require.Nil(t, state, "scenario should finish after hand-off")
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		require.NoError(t, store.Save(ctx, &ConversationState{ClientID: 3, Step: "phone", UpdatedAt: time.Now().Add(-2 * time.Hour)}), "store.Save() error")
+		inbox(3, "+7 (999) 123-45-67")
+		state, err := store.Load(ctx, 3)
+		require.NoError(t, err, "store.Load() error")
+		require.Equal(t, "menu", state.Step, "late answer should restart the scenario")
+	})
+
+	t.Run("Sweep", func(t *testing.T) {
+		store := NewMemoryStateStore()
+		scenario, err := dst.NewScenario(store,
+			&ScenarioStep{Name: "wait", Message: &MessagePayload{Text: "Wait"}, Timeout: time.Hour, TimeoutStep: "operator"},
+			&ScenarioStep{Name: "quiet", Timeout: time.Hour},
+			&ScenarioStep{Name: "operator", Message: &MessagePayload{Text: "Connecting to an operator"}, HandOff: 8},
+		)
+		require.NoError(t, err, "dst.NewScenario() error")
+
+		late := time.Now().Add(-2 * time.Hour)
+		require.NoError(t, store.Save(ctx, &ConversationState{ClientID: 5, Step: "wait", UpdatedAt: late, ChannelID: 6, MessageID: 500}), "store.Save() error")
+		require.NoError(t, store.Save(ctx, &ConversationState{ClientID: 6, Step: "quiet", UpdatedAt: late}), "store.Save() error")
+		require.NoError(t, store.Save(ctx, &ConversationState{ClientID: 7, Step: "wait", UpdatedAt: time.Now()}), "store.Save() error")
+
+		count, err := scenario.Sweep(ctx)
+		require.NoError(t, err, "scenario.Sweep() error")
+		require.Equal(t, 2, count, "timed out conversations should be counted")
+		require.Contains(t, fake.transfers, "/v1/messages/500/transfer_to_group?group_id=8&force=false", "timed out dialog should be handed off")
+		require.Equal(t, "Connecting to an operator", fake.texts()[len(fake.texts())-1], "timeout step message should be sent")
+		require.Equal(t, int64(6), fake.messages[len(fake.messages)-1].ChannelID, "message should be sent to the channel of the client")
+
+		states, err := store.List(ctx)
+		require.NoError(t, err, "store.List() error")
+		require.Len(t, states, 1, "timed out conversations should be finished")
+		require.Equal(t, int64(7), states[0].ClientID, "active conversation should be kept")
+
+		// The client answers between listing and timing out
+		listed := &ConversationState{ClientID: 8, Step: "wait", UpdatedAt: late}
+		require.NoError(t, store.Save(ctx, &ConversationState{ClientID: 8, Step: "wait", UpdatedAt: time.Now()}), "store.Save() error")
+		ok, err := scenario.timeout(ctx, listed)
+		require.NoError(t, err, "scenario.timeout() error")
+		require.False(t, ok, "changed conversation should not time out")
+		state, err := store.Load(ctx, 8)
+		require.NoError(t, err, "store.Load() error")
+		require.Equal(t, "wait", state.Step, "newer state should be kept")
+	})
+
+	t.Run("Ignored notifications", func(t *testing.T) {
+		count := len(fake.texts())
+		require.NoError(t, scenario.Handle(ctx, &WebhookNotification{HookType: "outbox", ClientID: 4}), "scenario.Handle() error")
+		require.NoError(t, scenario.Handle(ctx, &WebhookNotification{HookType: "inbox", Type: "system", ClientID: 4}), "scenario.Handle() error")
+		require.Len(t, fake.texts(), count, "only inbound client messages should be processed")
+	})
+}
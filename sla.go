@@ -0,0 +1,375 @@
+package ctd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RequestSLA holds the response and resolution times of a request.
+// If the calendar is set, only the working time counts.
+type RequestSLA struct {
+	RequestID       int64           // RequestID: ID of the request
+	DialogID        int64           // DialogID: ID of the dialog
+	ClientID        int64           // ClientID: ID of the client
+	OperatorID      int64           // OperatorID: Operator of the request (or the operator who answered last)
+	ChannelID       int64           // ChannelID: ID of the channel
+	Transport       string          // Transport: Transport of the request
+	Start           time.Time       // Start: Opening time of the request (or the time of the first message)
+	End             time.Time       // End: Closing time of the request (zero for open requests)
+	FirstResponse   time.Duration   // FirstResponse: Time from the first client message to the first operator answer
+	AverageResponse time.Duration   // AverageResponse: Average time the client waited for an operator answer
+	ResponseTimes   []time.Duration // ResponseTimes: Waiting times of the client before each operator answer
+	Resolution      time.Duration   // Resolution: Time from the opening to the closing of the request
+	Responded       bool            // Responded: The operator answered the client
+	Resolved        bool            // Resolved: The request is closed
+}
+
+// SLAAggregate holds the averages of the request SLAs of a group.
+type SLAAggregate struct {
+	Requests         int           // Requests: Number of requests
+	Responded        int           // Responded: Number of requests with an operator answer
+	Resolved         int           // Resolved: Number of closed requests
+	Responses        int           // Responses: Number of operator answers
+	FirstResponse    time.Duration // FirstResponse: Average first response time of the answered requests
+	MaxFirstResponse time.Duration // MaxFirstResponse: Maximum first response time
+	AverageResponse  time.Duration // AverageResponse: Average waiting time over all the answers
+	Resolution       time.Duration // Resolution: Average resolution time of the closed requests
+	WithinTarget     int           // WithinTarget: Number of answered requests with the first response within SLAOptions.FirstResponseTarget
+}
+
+// SLAReport holds the per-request SLAs and their aggregates.
+type SLAReport struct {
+	Requests   []RequestSLA           // Requests: SLAs of the requests
+	Total      SLAAggregate           // Total: Aggregate of all the requests
+	ByOperator map[int64]SLAAggregate // ByOperator: Aggregates by operator ID
+	ByChannel  map[int64]SLAAggregate // ByChannel: Aggregates by channel ID
+	Errors     map[int64]error        // Errors: Errors of the requests left out of the report, by request ID
+}
+
+// SLAOptions configures the SLA calculation.
+type SLAOptions struct {
+	Calendar            *BusinessCalendar // Calendar: Working hours; time outside them is not counted (nil - all time counts)
+	FirstResponseTarget time.Duration     // FirstResponseTarget: Target first response time counted by SLAAggregate.WithinTarget (0 - no target)
+	Workers             int               // Workers: Number of requests whose messages are loaded concurrently (default: 4)
+}
+
+// ComputeRequestSLA calculates the SLA of a request from its messages.
+// A response time runs from the first client message ('in') not yet answered to the next operator message ('out');
+// system messages are ignored.
+//
+// Parameters:
+//   - request: The request.
+//   - messages: The messages of the request in any order.
+//   - calendar: The working hours (nil - all time counts).
+//
+// Returns:
+//   - The SLA of the request.
+func ComputeRequestSLA(request *Request, messages []RequestMessage, calendar *BusinessCalendar) RequestSLA {
+	result := RequestSLA{
+		RequestID:  request.ID,
+		DialogID:   request.DialogID,
+		ClientID:   request.ClientID,
+		OperatorID: request.OperatorID,
+		ChannelID:  request.ChannelID,
+		Transport:  request.Transport,
+		Start:      request.StartTime(),
+	}
+	if request.IsClosed() {
+		result.End = request.EndTime()
+	}
+
+	sorted := make([]RequestMessage, len(messages))
+	copy(sorted, messages)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Created < sorted[j].Created
+	})
+
+	var waiting time.Time
+	operator := int64(0)
+	total := time.Duration(0)
+	for _, message := range sorted {
+		if result.Start.IsZero() {
+			result.Start = message.CreatedTime()
+		}
+		switch message.Type {
+		case "in":
+			if waiting.IsZero() {
+				waiting = message.CreatedTime()
+			}
+		case "out":
+			if message.OperatorID != 0 {
+				operator = message.OperatorID
+			}
+			if waiting.IsZero() {
+				continue
+			}
+			duration := workingDuration(calendar, waiting, message.CreatedTime())
+			if !result.Responded {
+				result.FirstResponse = duration
+				result.Responded = true
+			}
+			result.ResponseTimes = append(result.ResponseTimes, duration)
+			total += duration
+			waiting = time.Time{}
+		}
+	}
+
+	if result.OperatorID == 0 {
+		result.OperatorID = operator
+	}
+	if len(result.ResponseTimes) > 0 {
+		result.AverageResponse = total / time.Duration(len(result.ResponseTimes))
+	}
+	if !result.End.IsZero() && !result.Start.IsZero() {
+		result.Resolution = workingDuration(calendar, result.Start, result.End)
+		result.Resolved = true
+	}
+
+	return result
+}
+
+// AggregateSLA calculates the averages of the request SLAs.
+//
+// Parameters:
+//   - requests: The SLAs of the requests.
+//   - target: The target first response time (0 - WithinTarget is not counted).
+//
+// Returns:
+//   - The aggregate of the requests.
+func AggregateSLA(requests []RequestSLA, target time.Duration) SLAAggregate {
+	result := SLAAggregate{Requests: len(requests)}
+	var first, response, resolution time.Duration
+
+	for _, request := range requests {
+		if request.Responded {
+			result.Responded++
+			first += request.FirstResponse
+			if request.FirstResponse > result.MaxFirstResponse {
+				result.MaxFirstResponse = request.FirstResponse
+			}
+			if target > 0 && request.FirstResponse <= target {
+				result.WithinTarget++
+			}
+		}
+		for _, duration := range request.ResponseTimes {
+			response += duration
+			result.Responses++
+		}
+		if request.Resolved {
+			result.Resolved++
+			resolution += request.Resolution
+		}
+	}
+
+	if result.Responded > 0 {
+		result.FirstResponse = first / time.Duration(result.Responded)
+	}
+	if result.Responses > 0 {
+		result.AverageResponse = response / time.Duration(result.Responses)
+	}
+	if result.Resolved > 0 {
+		result.Resolution = resolution / time.Duration(result.Resolved)
+	}
+
+	return result
+}
+
+// NewSLAReport groups the request SLAs by operator and channel and calculates the aggregates.
+//
+// Parameters:
+//   - requests: The SLAs of the requests.
+//   - target: The target first response time (0 - WithinTarget is not counted).
+//
+// Returns:
+//   - A pointer to an SLAReport.
+func NewSLAReport(requests []RequestSLA, target time.Duration) *SLAReport {
+	operators := map[int64][]RequestSLA{}
+	channels := map[int64][]RequestSLA{}
+	for _, request := range requests {
+		operators[request.OperatorID] = append(operators[request.OperatorID], request)
+		channels[request.ChannelID] = append(channels[request.ChannelID], request)
+	}
+
+	result := &SLAReport{
+		Requests:   requests,
+		Total:      AggregateSLA(requests, target),
+		ByOperator: make(map[int64]SLAAggregate, len(operators)),
+		ByChannel:  make(map[int64]SLAAggregate, len(channels)),
+	}
+	for id, items := range operators {
+		result.ByOperator[id] = AggregateSLA(items, target)
+	}
+	for id, items := range channels {
+		result.ByChannel[id] = AggregateSLA(items, target)
+	}
+
+	return result
+}
+
+// GetRequestSLA loads the request with its messages and calculates its SLA.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - request_id: The ID of the request.
+//   - options: The SLA options (nil - defaults).
+//
+// Returns:
+//   - A pointer to the RequestSLA.
+//   - An error if the request or its messages can't be loaded.
+func (dst *Ctd) GetRequestSLA(ctx context.Context, request_id int64, options *SLAOptions) (*RequestSLA, error) {
+	opts := slaOptions(options)
+
+	request, err := dst.GetRequest(ctx, request_id)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := dst.RequestMessages(ctx, request_id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ComputeRequestSLA(request, messages, opts.Calendar)
+	return &result, nil
+}
+
+// GetDialogSLA calculates the SLA of the last request of the dialog.
+// The dialog begin and end times are used when the request has no opening or closing time.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - dialog_id: The ID of the dialog.
+//   - options: The SLA options (nil - defaults).
+//
+// Returns:
+//   - A pointer to the RequestSLA.
+//   - ErrorInvalidParameters if the dialog has no requests, or an error if the data can't be loaded.
+func (dst *Ctd) GetDialogSLA(ctx context.Context, dialog_id int64, options *SLAOptions) (*RequestSLA, error) {
+	opts := slaOptions(options)
+
+	dialog, err := dst.GetDialog(ctx, dialog_id)
+	if err != nil {
+		return nil, err
+	}
+	if dialog.LastRequestID == 0 {
+		return nil, ErrorInvalidParameters
+	}
+
+	request, err := dst.GetRequest(ctx, dialog.LastRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := dst.RequestMessages(ctx, dialog.LastRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Start == "" {
+		if !dialog.Begin.Time.IsZero() {
+			request.Start = dialog.Begin.Time.UTC().Format(time.RFC3339)
+		}
+	}
+	if request.End == "" && dialog.State == "closed" {
+		if !dialog.End.Time.IsZero() {
+			request.End = dialog.End.Time.UTC().Format(time.RFC3339)
+		}
+	}
+	if request.DialogID == 0 {
+		request.DialogID = dialog_id
+	}
+
+	result := ComputeRequestSLA(request, messages, opts.Calendar)
+	return &result, nil
+}
+
+// GetSLAReport loads the requests matching the parameters with their messages and calculates the SLA report.
+// Requests whose messages can't be loaded are left out of the report and recorded in SLAReport.Errors.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - params: The parameters of the requests (nil - all requests).
+//   - options: The SLA options (nil - defaults).
+//
+// Returns:
+//   - A pointer to the SLAReport, partial if some requests failed.
+//   - An error if the requests can't be loaded, or the joined errors of the requests whose messages can't be loaded.
+func (dst *Ctd) GetSLAReport(ctx context.Context, params *GetRequestsParams, options *SLAOptions) (*SLAReport, error) {
+	opts := slaOptions(options)
+
+	requests, err := dst.AllRequests(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]RequestSLA, len(requests))
+	errs := make([]error, len(requests))
+	jobs := make(chan int)
+
+	wg := sync.WaitGroup{}
+	for range opts.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				messages, err := dst.RequestMessages(ctx, requests[i].ID)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				results[i] = ComputeRequestSLA(&requests[i], messages, opts.Calendar)
+			}
+		}()
+	}
+
+	for i := range requests {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	loaded := make([]RequestSLA, 0, len(results))
+	failed := map[int64]error{}
+	for i, err := range errs {
+		if err != nil {
+			failed[requests[i].ID] = err
+			errs[i] = fmt.Errorf("request %d: %w", requests[i].ID, err)
+			continue
+		}
+		loaded = append(loaded, results[i])
+	}
+
+	report := NewSLAReport(loaded, opts.FirstResponseTarget)
+	if len(failed) > 0 {
+		report.Errors = failed
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// slaOptions returns a copy of the options with the defaults applied.
+func slaOptions(options *SLAOptions) SLAOptions {
+	result := SLAOptions{}
+	if options != nil {
+		result = *options
+	}
+	if result.Workers <= 0 {
+		result.Workers = 4
+	}
+	return result
+}
+
+// workingDuration returns the time between two moments, counting only the working hours if the calendar is set.
+func workingDuration(calendar *BusinessCalendar, from, to time.Time) time.Duration {
+	if calendar != nil {
+		return calendar.WorkingDuration(from, to)
+	}
+	if to.Before(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
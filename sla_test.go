@@ -0,0 +1,114 @@
+package ctd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeRequestSLA(t *testing.T) {
+	start := time.Date(2026, 1, 5, 17, 50, 0, 0, time.UTC)
+	at := func(minutes int) int64 {
+		return start.Add(time.Duration(minutes) * time.Minute).Unix()
+	}
+
+	request := &Request{
+		ID:        1,
+		ClientID:  10,
+		ChannelID: 100,
+		Transport: "telegram",
+		State:     "closed",
+		Start:     start.Format(time.RFC3339),
+		End:       start.Add(24 * time.Hour).Format(time.RFC3339),
+	}
+	messages := []RequestMessage{
+		{Type: "out", Created: at(21), OperatorID: 7},
+		{Type: "in", Created: at(0)},
+		{Type: "in", Created: at(5)},
+		{Type: "system", Created: at(6)},
+		{Type: "in", Created: at(30)},
+		{Type: "out", Created: at(31), OperatorID: 7},
+		{Type: "out", Created: at(32), OperatorID: 7},
+	}
+
+	sla := ComputeRequestSLA(request, messages, nil)
+	require.True(t, sla.Responded, "request should be responded")
+	require.True(t, sla.Resolved, "request should be resolved")
+	require.Equal(t, int64(7), sla.OperatorID, "operator should be taken from the answers")
+	require.Equal(t, 21*time.Minute, sla.FirstResponse, "first response")
+	require.Equal(t, []time.Duration{21 * time.Minute, time.Minute}, sla.ResponseTimes, "response times")
+	require.Equal(t, 11*time.Minute, sla.AverageResponse, "average response")
+	require.Equal(t, 24*time.Hour, sla.Resolution, "resolution")
+
+	calendar, err := NewBusinessCalendar(time.UTC, "09:00", "18:00", time.Monday, time.Tuesday)
+	require.NoError(t, err, "NewBusinessCalendar() error")
+
+	sla = ComputeRequestSLA(request, messages, calendar)
+	require.Equal(t, 10*time.Minute, sla.FirstResponse, "first response within working hours")
+	require.Equal(t, 9*time.Hour, sla.Resolution, "resolution within working hours")
+
+	open := &Request{ID: 2, OperatorID: 8, State: "open"}
+	sla = ComputeRequestSLA(open, messages[1:3], nil)
+	require.False(t, sla.Responded, "request without answers shouldn't be responded")
+	require.False(t, sla.Resolved, "open request shouldn't be resolved")
+	require.Equal(t, time.Unix(at(0), 0), sla.Start, "start should be taken from the first message")
+	require.Equal(t, int64(8), sla.OperatorID, "operator of the request")
+}
+
+func TestNewSLAReport(t *testing.T) {
+	requests := []RequestSLA{
+		{OperatorID: 1, ChannelID: 10, Responded: true, FirstResponse: time.Minute, ResponseTimes: []time.Duration{time.Minute, 3 * time.Minute}, Resolved: true, Resolution: time.Hour},
+		{OperatorID: 1, ChannelID: 20, Responded: true, FirstResponse: 5 * time.Minute, ResponseTimes: []time.Duration{5 * time.Minute}},
+		{OperatorID: 2, ChannelID: 10, Resolved: true, Resolution: 3 * time.Hour},
+	}
+
+	report := NewSLAReport(requests, 2*time.Minute)
+	require.Equal(t, SLAAggregate{
+		Requests:         3,
+		Responded:        2,
+		Resolved:         2,
+		Responses:        3,
+		FirstResponse:    3 * time.Minute,
+		MaxFirstResponse: 5 * time.Minute,
+		AverageResponse:  3 * time.Minute,
+		Resolution:       2 * time.Hour,
+		WithinTarget:     1,
+	}, report.Total, "total aggregate")
+
+	require.Len(t, report.ByOperator, 2, "operator aggregates")
+	require.Equal(t, 2, report.ByOperator[1].Requests, "requests of operator 1")
+	require.Equal(t, 0, report.ByOperator[2].Responded, "answered requests of operator 2")
+	require.Len(t, report.ByChannel, 2, "channel aggregates")
+	require.Equal(t, 2*time.Hour, report.ByChannel[10].Resolution, "resolution of channel 10")
+}
+
+func TestCtd_GetSLAReport(t *testing.T) {
+	ctx := t.Context()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/requests":
+			json.NewEncoder(w).Encode(map[string]any{"status": "success", "data": []map[string]any{{"id": 1, "operator_id": 5}, {"id": 2, "operator_id": 6}}})
+		case "/v1/requests/1/messages":
+			json.NewEncoder(w).Encode([]map[string]any{{"id": 1, "type": "in", "created": 1767600000}, {"id": 2, "type": "out", "created": 1767600060}})
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	dst := &Ctd{}
+	dst.Init(server.URL, "token")
+
+	report, err := dst.GetSLAReport(ctx, nil, nil)
+	require.Error(t, err, "dst.GetSLAReport() should return the failed requests")
+	require.NotNil(t, report, "dst.GetSLAReport() should return a partial report")
+	require.Len(t, report.Requests, 1, "loaded requests should be reported")
+	require.Equal(t, time.Minute, report.Requests[0].FirstResponse, "first response of the loaded request")
+	require.Contains(t, report.Errors, int64(2), "failed request should be recorded")
+	require.NotContains(t, report.ByOperator, int64(6), "failed request should not be aggregated")
+}
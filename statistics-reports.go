@@ -0,0 +1,312 @@
+package ctd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// StatisticsReport is the type of a statistics report.
+type StatisticsReport string
+
+const (
+	StatisticsReportRating     StatisticsReport = "rating"     // Ratings given by the clients
+	StatisticsReportOperators  StatisticsReport = "operators"  // Activity of the operators
+	StatisticsReportDialogs    StatisticsReport = "dialogs"    // Opened and closed dialogs
+	StatisticsReportMessages   StatisticsReport = "messages"   // Inbound and outbound messages
+	StatisticsReportRequests   StatisticsReport = "requests"   // Requests and their response times
+	StatisticsReportTags       StatisticsReport = "tags"       // Usage of the tags
+	StatisticsReportTransports StatisticsReport = "transports" // Load by transport
+	StatisticsReportChannels   StatisticsReport = "channels"   // Load by channel
+)
+
+// StatisticsQuery holds the parameters of a statistics report request.
+// Either Date or the StartDate/FinishDate range is sent; without both the current date is used.
+type StatisticsQuery struct {
+	Report     StatisticsReport // Report: Report type (default: the report of the row type)
+	Date       time.Time        // Date: Day of the report
+	StartDate  time.Time        // StartDate: Optional start of the date range (inclusive)
+	FinishDate time.Time        // FinishDate: Optional end of the date range (inclusive)
+	OperatorID int64            // OperatorID: Optional filter by operator ID
+	ChannelID  int64            // ChannelID: Optional filter by channel ID
+	Transport  string           // Transport: Optional filter by transport
+	TagID      int64            // TagID: Optional filter by tag ID
+	Limit      int              // Limit: Optional limit of rows to retrieve (default: 200 for iterators, max: 200)
+	Offset     int              // Offset: Optional offset for pagination (default: 0)
+}
+
+func (p *StatisticsQuery) Params() string {
+	params := []string{fmt.Sprintf("report=%s", url.QueryEscape(string(p.Report)))}
+
+	if !p.StartDate.IsZero() || !p.FinishDate.IsZero() {
+		if !p.StartDate.IsZero() {
+			params = append(params, fmt.Sprintf("start_date=%s", p.StartDate.Format("2006-01-02")))
+		}
+		if !p.FinishDate.IsZero() {
+			params = append(params, fmt.Sprintf("finish_date=%s", p.FinishDate.Format("2006-01-02")))
+		}
+	} else {
+		date := p.Date
+		if date.IsZero() {
+			date = time.Now()
+		}
+		params = append(params, fmt.Sprintf("date=%s", date.Format("2006-01-02")))
+	}
+
+	if p.OperatorID > 0 {
+		params = append(params, fmt.Sprintf("operator_id=%d", p.OperatorID))
+	}
+	if p.ChannelID > 0 {
+		params = append(params, fmt.Sprintf("channel_id=%d", p.ChannelID))
+	}
+	if p.Transport != "" {
+		params = append(params, fmt.Sprintf("transport=%s", url.QueryEscape(p.Transport)))
+	}
+	if p.TagID > 0 {
+		params = append(params, fmt.Sprintf("tag_id=%d", p.TagID))
+	}
+	params = append(params, fmt.Sprintf("offset=%d", p.Offset))
+	if p.Limit > 0 {
+		params = append(params, fmt.Sprintf("limit=%d", p.Limit))
+	}
+
+	return "?" + strings.Join(params, "&")
+}
+
+// StatisticsRow is a row type of a statistics report.
+type StatisticsRow interface {
+	// StatisticsReport returns the report the row belongs to.
+	StatisticsReport() StatisticsReport
+}
+
+type StatisticsOperator struct {
+	OperatorID      int64       `json:"operator_id"`       // OperatorID: ID of the operator
+	Name            string      `json:"name"`              // Name: Name of the operator
+	Requests        json.Number `json:"requests"`          // Requests: Number of requests handled
+	Dialogs         json.Number `json:"dialogs"`           // Dialogs: Number of dialogs handled
+	Messages        json.Number `json:"messages"`          // Messages: Number of messages sent
+	FirstResponse   json.Number `json:"first_answer_time"` // FirstResponse: Average first response time in seconds
+	AverageResponse json.Number `json:"avg_answer_time"`   // AverageResponse: Average response time in seconds
+	OnlineTime      json.Number `json:"online_time"`       // OnlineTime: Time online in seconds
+	Rating          json.Number `json:"rating"`            // Rating: Average rating of the requests
+}
+
+type StatisticsDialog struct {
+	Date       string      `json:"date"`       // Date: Day or hour of the row
+	Opened     json.Number `json:"opened"`     // Opened: Number of dialogs opened
+	Closed     json.Number `json:"closed"`     // Closed: Number of dialogs closed
+	Unread     json.Number `json:"unread"`     // Unread: Number of dialogs with unread messages
+	Unanswered json.Number `json:"unanswered"` // Unanswered: Number of dialogs without an operator answer
+}
+
+type StatisticsMessage struct {
+	Date      string      `json:"date"`      // Date: Day or hour of the row
+	Inbox     json.Number `json:"inbox"`     // Inbox: Number of messages from the clients
+	Outbox    json.Number `json:"outbox"`    // Outbox: Number of messages to the clients
+	System    json.Number `json:"system"`    // System: Number of system messages
+	Autoreply json.Number `json:"autoreply"` // Autoreply: Number of automatic replies
+	Total     json.Number `json:"total"`     // Total: Total number of messages
+}
+
+type StatisticsRequest struct {
+	Date            string      `json:"date"`              // Date: Day or hour of the row
+	Opened          json.Number `json:"opened"`            // Opened: Number of requests opened
+	Closed          json.Number `json:"closed"`            // Closed: Number of requests closed
+	Unanswered      json.Number `json:"unanswered"`        // Unanswered: Number of requests without an operator answer
+	FirstResponse   json.Number `json:"first_answer_time"` // FirstResponse: Average first response time in seconds
+	AverageResponse json.Number `json:"avg_answer_time"`   // AverageResponse: Average response time in seconds
+	Duration        json.Number `json:"duration"`          // Duration: Average request duration in seconds
+}
+
+type StatisticsTag struct {
+	TagID    int64       `json:"tag_id"`   // TagID: ID of the tag
+	Name     string      `json:"name"`     // Name: Name of the tag
+	Requests json.Number `json:"requests"` // Requests: Number of requests with the tag
+	Clients  json.Number `json:"clients"`  // Clients: Number of clients with the tag
+}
+
+type StatisticsTransport struct {
+	Transport string      `json:"transport"` // Transport: Transport
+	Clients   json.Number `json:"clients"`   // Clients: Number of clients who wrote
+	Requests  json.Number `json:"requests"`  // Requests: Number of requests
+	Inbox     json.Number `json:"inbox"`     // Inbox: Number of messages from the clients
+	Outbox    json.Number `json:"outbox"`    // Outbox: Number of messages to the clients
+}
+
+type StatisticsChannel struct {
+	ChannelID int64       `json:"channel_id"` // ChannelID: ID of the channel
+	Name      string      `json:"name"`       // Name: Name of the channel
+	Clients   json.Number `json:"clients"`    // Clients: Number of clients who wrote
+	Requests  json.Number `json:"requests"`   // Requests: Number of requests
+	Inbox     json.Number `json:"inbox"`      // Inbox: Number of messages from the clients
+	Outbox    json.Number `json:"outbox"`     // Outbox: Number of messages to the clients
+}
+
+func (StatisticsRating) StatisticsReport() StatisticsReport    { return StatisticsReportRating }
+func (StatisticsOperator) StatisticsReport() StatisticsReport  { return StatisticsReportOperators }
+func (StatisticsDialog) StatisticsReport() StatisticsReport    { return StatisticsReportDialogs }
+func (StatisticsMessage) StatisticsReport() StatisticsReport   { return StatisticsReportMessages }
+func (StatisticsRequest) StatisticsReport() StatisticsReport   { return StatisticsReportRequests }
+func (StatisticsTag) StatisticsReport() StatisticsReport       { return StatisticsReportTags }
+func (StatisticsTransport) StatisticsReport() StatisticsReport { return StatisticsReportTransports }
+func (StatisticsChannel) StatisticsReport() StatisticsReport   { return StatisticsReportChannels }
+
+type StatisticsResponse[T StatisticsRow] struct {
+	Data []T          `json:"data"`
+	Meta MetaResponse `json:"meta"`
+	BasicResponse
+}
+
+// APIStatistics retrieves a page of a statistics report from the Chat2Desk API.
+// If the report of the query isn't set, the report of the row type is used.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - dst: The API client.
+//   - query: The report parameters (nil - the current date).
+//
+// Returns:
+//   - A pointer to a StatisticsResponse containing the rows and metadata.
+//   - An error if the request fails or if the response is invalid.
+func APIStatistics[T StatisticsRow](ctx context.Context, dst *Ctd, query *StatisticsQuery) (*StatisticsResponse[T], error) {
+	params := statisticsQuery[T](query)
+	url := fmt.Sprintf("%sv1/statistics%s", dst.Url, params.Params())
+
+	response := StatisticsResponse[T]{}
+
+	if _, err := dst.doRequest(ctx, "GET", url, nil, &response); err != nil {
+		dst.Error(ctx, "Failed to get statistics: %v", err)
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// GetStatistics retrieves a page of a statistics report.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - dst: The API client.
+//   - query: The report parameters (nil - the current date).
+//
+// Returns:
+//   - A slice of the rows.
+//   - The total number of rows available (for pagination).
+//   - An error if the request fails or if the response is invalid.
+func GetStatistics[T StatisticsRow](ctx context.Context, dst *Ctd, query *StatisticsQuery) ([]T, int, error) {
+	response, err := APIStatistics[T](ctx, dst, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if response.Status != "success" {
+		dst.Error(ctx, "Failed to get statistics: %v", response.Errors)
+		return nil, 0, ErrorInvalidResponse
+	}
+
+	return response.Data, response.Meta.Total, nil
+}
+
+// StatisticsPages returns an iterator over the pages of a statistics report, starting at the query offset.
+// The iteration stops after the first error, which is yielded with a nil page.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - dst: The API client.
+//   - query: The report parameters (nil - the current date).
+//
+// Returns:
+//   - An iterator over the pages and errors.
+func StatisticsPages[T StatisticsRow](ctx context.Context, dst *Ctd, query *StatisticsQuery) iter.Seq2[[]T, error] {
+	return func(yield func([]T, error) bool) {
+		params := statisticsQuery[T](query)
+		if params.Limit <= 0 {
+			params.Limit = 200
+		}
+
+		for {
+			rows, total, err := GetStatistics[T](ctx, dst, &params)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if len(rows) == 0 {
+				return
+			}
+			if !yield(rows, nil) {
+				return
+			}
+
+			params.Offset += len(rows)
+			if len(rows) < params.Limit || (total > 0 && params.Offset >= total) {
+				return
+			}
+		}
+	}
+}
+
+// StatisticsRows returns an iterator over the rows of all pages of a statistics report.
+// The iteration stops after the first error, which is yielded with a zero row.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - dst: The API client.
+//   - query: The report parameters (nil - the current date).
+//
+// Returns:
+//   - An iterator over the rows and errors.
+func StatisticsRows[T StatisticsRow](ctx context.Context, dst *Ctd, query *StatisticsQuery) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for rows, err := range StatisticsPages[T](ctx, dst, query) {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, row := range rows {
+				if !yield(row, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// AllStatistics retrieves all rows of a statistics report by handling pagination.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - dst: The API client.
+//   - query: The report parameters (nil - the current date).
+//
+// Returns:
+//   - A slice of all the rows.
+//   - An error if the request fails or if the response is invalid.
+func AllStatistics[T StatisticsRow](ctx context.Context, dst *Ctd, query *StatisticsQuery) ([]T, error) {
+	result := []T{}
+	for rows, err := range StatisticsPages[T](ctx, dst, query) {
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, rows...)
+	}
+
+	return result, nil
+}
+
+// statisticsQuery returns a copy of the query with the report of the row type set if empty.
+func statisticsQuery[T StatisticsRow](query *StatisticsQuery) StatisticsQuery {
+	result := StatisticsQuery{}
+	if query != nil {
+		result = *query
+	}
+	if result.Report == "" {
+		var row T
+		result.Report = row.StatisticsReport()
+	}
+	return result
+}
@@ -0,0 +1,82 @@
+package ctd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatisticsQuery_Params(t *testing.T) {
+	date := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	query := StatisticsQuery{Report: StatisticsReportOperators, Date: date, OperatorID: 7, Limit: 50}
+	require.Equal(t, "?report=operators&date=2026-01-05&operator_id=7&offset=0&limit=50", query.Params(), "query.Params()")
+
+	query = StatisticsQuery{Report: StatisticsReportChannels, Date: date, StartDate: date, FinishDate: date.AddDate(0, 0, 6), Transport: "telegram", Offset: 200}
+	require.Equal(t, "?report=channels&start_date=2026-01-05&finish_date=2026-01-11&transport=telegram&offset=200", query.Params(), "the range should replace the date")
+}
+
+func TestStatisticsPages(t *testing.T) {
+	ctx := context.Background()
+	rows := make([]map[string]any, 5)
+	for i := range rows {
+		rows[i] = map[string]any{"channel_id": i + 1, "name": "channel", "inbox": i * 10}
+	}
+
+	reports := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		reports = append(reports, query.Get("report"))
+		if query.Get("report") == string(StatisticsReportTags) {
+			w.Write([]byte(`{"status":"error","errors":"unknown report"}`))
+			return
+		}
+
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		end := min(offset+limit, len(rows))
+		json.NewEncoder(w).Encode(map[string]any{
+			"status": "success",
+			"data":   rows[offset:end],
+			"meta":   map[string]any{"total": len(rows), "offset": offset, "limit": limit},
+		})
+	}))
+	defer server.Close()
+
+	dst := &Ctd{}
+	dst.Init(server.URL, "token")
+
+	pages := 0
+	for page, err := range StatisticsPages[StatisticsChannel](ctx, dst, &StatisticsQuery{Limit: 2}) {
+		require.NoError(t, err, "StatisticsPages() error")
+		require.NotEmpty(t, page, "page should not be empty")
+		pages++
+	}
+	require.Equal(t, 3, pages, "all the pages should be walked")
+	require.Equal(t, string(StatisticsReportChannels), reports[0], "the report of the row type should be requested")
+
+	data, err := AllStatistics[StatisticsChannel](ctx, dst, nil)
+	require.NoError(t, err, "AllStatistics() error")
+	require.Len(t, data, 5, "all the rows should be returned")
+	require.Equal(t, int64(5), data[4].ChannelID, "rows should keep the order")
+	require.Equal(t, "40", data[4].Inbox.String(), "numbers should be decoded")
+
+	ids := []int64{}
+	for row, err := range StatisticsRows[StatisticsChannel](ctx, dst, &StatisticsQuery{Limit: 2}) {
+		require.NoError(t, err, "StatisticsRows() error")
+		ids = append(ids, row.ChannelID)
+		if len(ids) == 3 {
+			break
+		}
+	}
+	require.Equal(t, []int64{1, 2, 3}, ids, "iteration should stop on break")
+
+	_, err = AllStatistics[StatisticsTag](ctx, dst, nil)
+	require.ErrorIs(t, err, ErrorInvalidResponse, "error status should be returned")
+}
@@ -123,8 +123,6 @@ func (dst *Ctd) StatisticsRating(ctx context.Context, date time.Time, offset int
 		return nil, 0, err
 	}
 
-	fmt.Printf("StatisticsRating: %+v\n", data)
-
 	if data.Status == "error" {
 		dst.Error(ctx, "Failed to get statistics: %s", data.Errors)
 		return nil, 0, fmt.Errorf("failed to get statistics: %s", data.Errors)
@@ -0,0 +1,249 @@
+package ctd
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TagRegistry caches the tags of the company and resolves them by label.
+// Tags are loaded with GetAllTags on first use and reloaded when the cache is older than TTL.
+// Labels and group names are matched case-insensitively.
+type TagRegistry struct {
+	TTL               time.Duration // TTL: Cache lifetime (default: 5 minutes)
+	AutoCreate        bool          // AutoCreate: Create missing tags when resolving labels
+	AutoCreateGroupID int           // AutoCreateGroupID: Group for auto-created tags when no group is specified (0 - API default)
+
+	ctd    *Ctd
+	mu     sync.RWMutex
+	tags   []Tag
+	loaded time.Time
+}
+
+// NewTagRegistry creates a new TagRegistry with the specified cache lifetime.
+//
+// Parameters:
+//   - ttl: The cache lifetime. If zero, 5 minutes is used.
+//
+// Returns:
+//   - A pointer to a TagRegistry.
+func (dst *Ctd) NewTagRegistry(ttl time.Duration) *TagRegistry {
+	return &TagRegistry{
+		TTL: ttl,
+		ctd: dst,
+	}
+}
+
+// Refresh reloads the tags from the Chat2Desk API.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//
+// Returns:
+//   - An error if the request fails or if the response is invalid.
+func (dst *TagRegistry) Refresh(ctx context.Context) error {
+	tags, err := dst.ctd.GetAllTags(ctx)
+	if err != nil {
+		return err
+	}
+
+	dst.mu.Lock()
+	dst.tags = tags
+	dst.loaded = time.Now()
+	dst.mu.Unlock()
+
+	return nil
+}
+
+// Tags returns the cached tags, reloading them if the cache is empty or expired.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//
+// Returns:
+//   - A slice of Tag, which contains all the tags.
+//   - An error if the request fails or if the response is invalid.
+func (dst *TagRegistry) Tags(ctx context.Context) ([]Tag, error) {
+	ttl := dst.TTL
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+
+	dst.mu.RLock()
+	expired := dst.loaded.IsZero() || time.Since(dst.loaded) > ttl
+	dst.mu.RUnlock()
+
+	if expired {
+		if err := dst.Refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	dst.mu.RLock()
+	defer dst.mu.RUnlock()
+
+	return append([]Tag{}, dst.tags...), nil
+}
+
+// Lookup finds a tag by its label.
+// If group is not empty, only tags of the group with this name are considered.
+// If several tags match, the one with the lowest ID is returned.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - label: The label of the tag.
+//   - group: The name of the tag group (optional).
+//
+// Returns:
+//   - A pointer to a Tag, which contains the tag data.
+//   - ErrorInvalidTagID if the tag is not found, or another error if the request fails.
+func (dst *TagRegistry) Lookup(ctx context.Context, label, group string) (*Tag, error) {
+	tags, err := dst.Tags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if tag := findTag(tags, label, group); tag != nil {
+		return tag, nil
+	}
+
+	return nil, ErrorInvalidTagID
+}
+
+// Resolve converts tag labels to tag IDs.
+// If AutoCreate is enabled, missing tags are created (and the group too, if it doesn't exist).
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - group: The name of the tag group (optional).
+//   - labels: The labels of the tags.
+//
+// Returns:
+//   - A slice of tag IDs in the order of the labels.
+//   - ErrorInvalidTagID if a tag is not found and AutoCreate is disabled, or another error if the request fails.
+func (dst *TagRegistry) Resolve(ctx context.Context, group string, labels ...string) ([]int64, error) {
+	ids := make([]int64, 0, len(labels))
+	for _, label := range labels {
+		tag, err := dst.Lookup(ctx, label, group)
+		if err == ErrorInvalidTagID && dst.AutoCreate {
+			tag, err = dst.create(ctx, label, group)
+		}
+		if err != nil {
+			if err == ErrorInvalidTagID {
+				dst.ctd.Error(ctx, "Tag not found: %s", label)
+			}
+			return nil, err
+		}
+		ids = append(ids, int64(tag.ID))
+	}
+
+	return ids, nil
+}
+
+// AddTagsToClientByLabel assigns tags identified by their labels to a client.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the client.
+//   - group: The name of the tag group (optional).
+//   - labels: The labels of the tags.
+//
+// Returns:
+//   - An error if a tag is not found, if the request fails or if the response is invalid.
+func (dst *TagRegistry) AddTagsToClientByLabel(ctx context.Context, id int64, group string, labels ...string) error {
+	ids, err := dst.Resolve(ctx, group, labels...)
+	if err != nil {
+		return err
+	}
+
+	return dst.ctd.AddTagToClient(ctx, ids, id)
+}
+
+// AddTagsToRequestByLabel assigns tags identified by their labels to a request.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the request.
+//   - group: The name of the tag group (optional).
+//   - labels: The labels of the tags.
+//
+// Returns:
+//   - An error if a tag is not found, if the request fails or if the response is invalid.
+func (dst *TagRegistry) AddTagsToRequestByLabel(ctx context.Context, id int64, group string, labels ...string) error {
+	ids, err := dst.Resolve(ctx, group, labels...)
+	if err != nil {
+		return err
+	}
+
+	return dst.ctd.AddTagToRequest(ctx, ids, id)
+}
+
+// create creates a missing tag and adds it to the cache.
+func (dst *TagRegistry) create(ctx context.Context, label, group string) (*Tag, error) {
+	groupID := dst.AutoCreateGroupID
+	if group != "" {
+		id, err := dst.groupID(ctx, group)
+		if err != nil {
+			return nil, err
+		}
+		groupID = id
+	}
+
+	tag, err := dst.ctd.CreateTag(ctx, &TagPayload{GroupID: groupID, Label: label})
+	if err != nil {
+		return nil, err
+	}
+	if tag.GroupName == "" {
+		tag.GroupName = group
+	}
+
+	dst.mu.Lock()
+	dst.tags = append(dst.tags, *tag)
+	dst.mu.Unlock()
+
+	return tag, nil
+}
+
+// groupID returns the ID of the tag group with the specified name, creating the group if it doesn't exist.
+func (dst *TagRegistry) groupID(ctx context.Context, name string) (int, error) {
+	groups, err := dst.ctd.GetTagGroups(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, group := range groups {
+		if strings.EqualFold(group.Name, name) {
+			return group.ID, nil
+		}
+	}
+
+	group, err := dst.ctd.CreateTagGroup(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	return group.ID, nil
+}
+
+// findTag returns the tag with the lowest ID matching the label and, optionally, the group name.
+func findTag(tags []Tag, label, group string) *Tag {
+	label = strings.TrimSpace(label)
+	group = strings.TrimSpace(group)
+
+	var result *Tag
+	for i := range tags {
+		if !strings.EqualFold(strings.TrimSpace(tags[i].Label), label) {
+			continue
+		}
+		if group != "" && !strings.EqualFold(strings.TrimSpace(tags[i].GroupName), group) {
+			continue
+		}
+		if result == nil || tags[i].ID < result.ID {
+			tag := tags[i]
+			result = &tag
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,76 @@
+package ctd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ra-company/env"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCtd_TagRegistry(t *testing.T) {
+	ctx := context.Background()
+
+	url, token := getCredentials(t)
+
+	tagID := int64(env.GetEnvInt("API_TAG_ID", 0))
+	require.NotEqual(t, int64(0), tagID, "API_TAG_ID must be set in .env file or .settings")
+
+	dst := &Ctd{}
+	dst.Init(url, token)
+
+	tag, err := dst.GetTag(ctx, tagID)
+	require.NoError(t, err, "dst.GetTag() error")
+
+	registry := dst.NewTagRegistry(time.Minute)
+
+	t.Run("Lookup", func(t *testing.T) {
+		got, err := registry.Lookup(ctx, tag.Label, "")
+		require.NoError(t, err, "registry.Lookup() error")
+		require.Equal(t, tag.Label, got.Label, "registry.Lookup() should return tag with the same label")
+
+		_, err = registry.Lookup(ctx, "not existing tag label", "")
+		require.ErrorIs(t, err, ErrorInvalidTagID, "registry.Lookup() error")
+	})
+
+	clientID := env.GetEnvInt("API_CLIENT_ID", 0)
+	require.NotEqual(t, 0, clientID, "API_CLIENT_ID must be set in .env file or .settings")
+
+	t.Run("AddTagsToClientByLabel", func(t *testing.T) {
+		err := registry.AddTagsToClientByLabel(ctx, int64(clientID), tag.GroupName, tag.Label)
+		require.NoError(t, err, "registry.AddTagsToClientByLabel() error")
+	})
+}
+
+func TestFindTag(t *testing.T) {
+	tags := []Tag{
+		{ID: 3, Label: "VIP", GroupName: "Clients"},
+		{ID: 2, Label: "vip", GroupName: "Partners"},
+		{ID: 5, Label: "Complaint", GroupName: "Requests"},
+	}
+
+	tests := []struct {
+		name  string
+		label string
+		group string
+		want  int
+	}{
+		{name: "Case-insensitive label", label: "complaint", want: 5},
+		{name: "Lowest ID without group", label: "Vip", want: 2},
+		{name: "Scoped by group", label: "VIP", group: "clients", want: 3},
+		{name: "Not found in group", label: "Complaint", group: "Clients", want: 0},
+		{name: "Not found", label: "unknown", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findTag(tags, tt.label, tt.group)
+			if tt.want == 0 {
+				require.Nil(t, got, "findTag() should return nil")
+				return
+			}
+			require.NotNil(t, got, "findTag() should return tag")
+			require.Equal(t, tt.want, got.ID, "findTag()")
+		})
+	}
+}
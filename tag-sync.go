@@ -0,0 +1,126 @@
+package ctd
+
+import (
+	"context"
+	"sync"
+)
+
+// TagSyncReport describes the changes made to the tags of a client by SyncClientTags.
+type TagSyncReport struct {
+	ClientID int64   // ClientID: ID of the client
+	Added    []int64 // Added: IDs of the assigned tags
+	Removed  []int64 // Removed: IDs of the removed tags
+	Error    error   // Error: Error that stopped the synchronization (batched variant only)
+}
+
+// Changed reports whether any tag was assigned or removed.
+func (dst *TagSyncReport) Changed() bool {
+	return len(dst.Added) > 0 || len(dst.Removed) > 0
+}
+
+// SyncClientTags makes the tags of a client equal to the desired set.
+// It reads the current tags with GetClient, assigns the missing tags with one AddTagToClient call
+// and removes the extra tags with RemoveTagFromClient.
+// If group is not zero, only the tags of this tag group are removed, so tags of other groups are kept.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the client.
+//   - desired: The IDs of the tags the client must have.
+//   - group: The ID of the tag group to limit the synchronization to (0 - all tags).
+//
+// Returns:
+//   - A pointer to a TagSyncReport describing the applied changes (partial on error).
+//   - An error if the request fails or if the response is invalid.
+func (dst *Ctd) SyncClientTags(ctx context.Context, id int64, desired []int64, group int) (*TagSyncReport, error) {
+	report := &TagSyncReport{ClientID: id}
+
+	client, err := dst.GetClient(ctx, int(id))
+	if err != nil {
+		if err == ErrorInvalidID {
+			return report, ErrorInvalidClientID
+		}
+		return report, err
+	}
+
+	current := []int64{}
+	for _, tag := range client.Tags {
+		if group == 0 || tag.GroupID == group {
+			current = append(current, int64(tag.ID))
+		}
+	}
+
+	// Desired tags may belong to other groups and already be assigned
+	assigned := make([]int64, 0, len(client.Tags))
+	for _, tag := range client.Tags {
+		assigned = append(assigned, int64(tag.ID))
+	}
+
+	added, _ := diffIDs(assigned, desired)
+	_, removed := diffIDs(current, desired)
+
+	if len(added) > 0 {
+		if err := dst.AddTagToClient(ctx, added, id); err != nil {
+			return report, err
+		}
+		report.Added = added
+	}
+
+	for _, tag := range removed {
+		if err := dst.RemoveTagFromClient(ctx, tag, id); err != nil {
+			return report, err
+		}
+		report.Removed = append(report.Removed, tag)
+	}
+
+	return report, nil
+}
+
+// SyncClientsTags synchronizes the tags of many clients using SyncClientTags.
+// Clients are processed concurrently by the specified number of workers.
+// Errors don't stop the processing of other clients and are reported in the TagSyncReport of every client.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - desired: The IDs of the tags every client must have, by client ID.
+//   - group: The ID of the tag group to limit the synchronization to (0 - all tags).
+//   - workers: The number of concurrent workers (default: 4).
+//
+// Returns:
+//   - A slice of TagSyncReport, one per client, in no particular order.
+func (dst *Ctd) SyncClientsTags(ctx context.Context, desired map[int64][]int64, group int, workers int) []TagSyncReport {
+	if workers <= 0 {
+		workers = 4
+	}
+
+	jobs := make(chan int64)
+	results := make(chan TagSyncReport, len(desired))
+
+	wg := sync.WaitGroup{}
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				report, err := dst.SyncClientTags(ctx, id, desired[id], group)
+				report.Error = err
+				results <- *report
+			}
+		}()
+	}
+
+	for id := range desired {
+		jobs <- id
+	}
+	close(jobs)
+
+	wg.Wait()
+	close(results)
+
+	reports := make([]TagSyncReport, 0, len(desired))
+	for report := range results {
+		reports = append(reports, report)
+	}
+
+	return reports
+}
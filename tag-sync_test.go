@@ -0,0 +1,60 @@
+package ctd
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/ra-company/env"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCtd_SyncClientTags(t *testing.T) {
+	ctx := context.Background()
+
+	url, token := getCredentials(t)
+
+	tagID := int64(env.GetEnvInt("API_TAG_ID", 0))
+	require.NotEqual(t, int64(0), tagID, "API_TAG_ID must be set in .env file or .settings")
+	clientID := int64(env.GetEnvInt("API_CLIENT_ID", 0))
+	require.NotEqual(t, int64(0), clientID, "API_CLIENT_ID must be set in .env file or .settings")
+
+	dst := &Ctd{}
+	dst.Init(url, token)
+
+	tag, err := dst.GetTag(ctx, tagID)
+	require.NoError(t, err, "dst.GetTag() error")
+
+	t.Run("01 Add tag", func(t *testing.T) {
+		_ = dst.RemoveTagFromClient(ctx, tagID, clientID)
+
+		report, err := dst.SyncClientTags(ctx, clientID, []int64{tagID}, tag.GroupID)
+		require.NoError(t, err, "dst.SyncClientTags() error")
+		require.Equal(t, []int64{tagID}, report.Added, "dst.SyncClientTags() should add the tag")
+		require.Empty(t, report.Removed, "dst.SyncClientTags() should not remove tags")
+	})
+
+	t.Run("02 Nothing to change", func(t *testing.T) {
+		report, err := dst.SyncClientTags(ctx, clientID, []int64{tagID}, tag.GroupID)
+		require.NoError(t, err, "dst.SyncClientTags() error")
+		require.False(t, report.Changed(), "dst.SyncClientTags() should not change tags")
+	})
+
+	t.Run("03 Remove tag within group", func(t *testing.T) {
+		report, err := dst.SyncClientTags(ctx, clientID, []int64{}, tag.GroupID)
+		require.NoError(t, err, "dst.SyncClientTags() error")
+		require.True(t, slices.Contains(report.Removed, tagID), "dst.SyncClientTags() should remove the tag")
+	})
+
+	t.Run("04 Batched", func(t *testing.T) {
+		reports := dst.SyncClientsTags(ctx, map[int64][]int64{clientID: {tagID}, 0: {tagID}}, tag.GroupID, 2)
+		require.Len(t, reports, 2, "dst.SyncClientsTags() should return report for every client")
+		for _, report := range reports {
+			if report.ClientID == 0 {
+				require.Error(t, report.Error, "dst.SyncClientsTags() should report error for invalid client")
+			} else {
+				require.NoError(t, report.Error, "dst.SyncClientsTags() error")
+			}
+		}
+	})
+}
@@ -32,6 +32,38 @@ type Tag struct {
 	Description string `json:"description"` // Description: Description of the tag
 }
 
+// TagPayload represents the payload structure for creating or updating a tag.
+type TagPayload struct {
+	GroupID     int    `json:"tag_group_id,omitempty"` // GroupID: Identifier of the group the tag belongs to
+	Label       string `json:"label,omitempty"`        // Label: Name of the tag
+	Description string `json:"description,omitempty"`  // Description: Description of the tag
+}
+
+// TagChangeResponse represents the response structure for creating or updating a tag.
+type TagChangeResponse struct {
+	BasicResponse
+	Data Tag `json:"data"` // Data: The tag item
+}
+
+// TagGroup represents a group of tags.
+type TagGroup struct {
+	ID    int    `json:"id"`    // ID: Unique identifier for the tag group
+	Name  string `json:"name"`  // Name: Name of the tag group
+	Order int    `json:"order"` // Order: Order of the tag group in the list
+}
+
+// TagGroupsResponse represents the response structure for the tag groups API.
+type TagGroupsResponse struct {
+	BasicResponse
+	Data []TagGroup `json:"data"` // Data: List of tag groups
+}
+
+// TagGroupResponse represents the response structure for creating a tag group.
+type TagGroupResponse struct {
+	BasicResponse
+	Data TagGroup `json:"data"` // Data: The tag group item
+}
+
 // GetTags retrieves a list of tags from the Chat2Desk API.
 // It uses the APIGetTags method to fetch the tags and handles errors.
 // It returns a pointer to a slice of Tag, which contains the tags.
@@ -370,3 +402,318 @@ func (dest *Ctd) RemoveTagFromClient(ctx context.Context, tag_id int64, id int64
 
 	return nil
 }
+
+// APICreateTag creates a new tag in the Chat2Desk API.
+// It constructs the API endpoint URL, sends a POST request with the payload,
+// and returns the response data as a TagChangeResponse struct.
+// If an error occurs during the request, it logs the error and returns it.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - payload: The TagPayload containing the details of the tag to be created.
+//
+// Returns:
+//   - A pointer to a TagChangeResponse struct containing the created tag
+//   - An error if the request fails or if the response is invalid.
+func (dest *Ctd) APICreateTag(ctx context.Context, payload *TagPayload) (*TagChangeResponse, error) {
+	url := fmt.Sprintf("%sv1/tags", dest.Url)
+	response := TagChangeResponse{}
+	_, err := dest.doRequest(ctx, "POST", url, payload, &response)
+	if err != nil {
+		dest.Error(ctx, "Failed to create tag: %v", err)
+		return nil, err
+	}
+	return &response, nil
+}
+
+// APIUpdateTag updates an existing tag in the Chat2Desk API.
+// It constructs the API endpoint URL with the tag ID, sends a PUT request with the payload,
+// and returns the response data as a TagChangeResponse struct.
+// If an error occurs during the request, it logs the error and returns it.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the tag to update.
+//   - payload: The TagPayload containing the updated details of the tag.
+//
+// Returns:
+//   - A pointer to a TagChangeResponse struct containing the updated tag
+//   - An error if the request fails or if the response is invalid.
+func (dest *Ctd) APIUpdateTag(ctx context.Context, id int64, payload *TagPayload) (*TagChangeResponse, error) {
+	url := fmt.Sprintf("%sv1/tags/%d", dest.Url, id)
+	response := TagChangeResponse{}
+	_, err := dest.doRequest(ctx, "PUT", url, payload, &response)
+	if err != nil {
+		dest.Error(ctx, "Failed to update tag: %v", err)
+		return nil, err
+	}
+	return &response, nil
+}
+
+// APIDeleteTag deletes a tag in the Chat2Desk API.
+// It constructs the API endpoint URL with the tag ID, sends a DELETE request,
+// and returns the response data as a BasicResponse struct.
+// If an error occurs during the request, it logs the error and returns it.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the tag to delete.
+//
+// Returns:
+//   - A pointer to a BasicResponse struct containing the response data
+//   - An error if the request fails or if the response is invalid.
+func (dest *Ctd) APIDeleteTag(ctx context.Context, id int64) (*BasicResponse, error) {
+	url := fmt.Sprintf("%sv1/tags/%d", dest.Url, id)
+	response := BasicResponse{}
+	_, err := dest.doRequest(ctx, "DELETE", url, nil, &response)
+	if err != nil {
+		dest.Error(ctx, "Failed to delete tag: %v", err)
+		return nil, err
+	}
+	return &response, nil
+}
+
+// APIGetTagGroups retrieves a list of tag groups from the Chat2Desk API.
+// It constructs the API endpoint URL, sends a GET request,
+// and returns the response data as a TagGroupsResponse struct.
+// If an error occurs during the request, it logs the error and returns it.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//
+// Returns:
+//   - A pointer to a TagGroupsResponse struct containing the list of tag groups
+//   - An error if the request fails or if the response is invalid.
+func (dest *Ctd) APIGetTagGroups(ctx context.Context) (*TagGroupsResponse, error) {
+	url := fmt.Sprintf("%sv1/tag_groups", dest.Url)
+	response := TagGroupsResponse{}
+	_, err := dest.doRequest(ctx, "GET", url, nil, &response)
+	if err != nil {
+		dest.Error(ctx, "Failed to get tag groups: %v", err)
+		return nil, err
+	}
+	return &response, nil
+}
+
+// APICreateTagGroup creates a new tag group in the Chat2Desk API.
+// It constructs the API endpoint URL, sends a POST request with the group name,
+// and returns the response data as a TagGroupResponse struct.
+// If an error occurs during the request, it logs the error and returns it.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - name: The name of the tag group.
+//
+// Returns:
+//   - A pointer to a TagGroupResponse struct containing the created tag group
+//   - An error if the request fails or if the response is invalid.
+func (dest *Ctd) APICreateTagGroup(ctx context.Context, name string) (*TagGroupResponse, error) {
+	url := fmt.Sprintf("%sv1/tag_groups", dest.Url)
+	payload := map[string]string{
+		"name": name,
+	}
+	response := TagGroupResponse{}
+	_, err := dest.doRequest(ctx, "POST", url, payload, &response)
+	if err != nil {
+		dest.Error(ctx, "Failed to create tag group: %v", err)
+		return nil, err
+	}
+	return &response, nil
+}
+
+// APIDeleteTagGroup deletes a tag group in the Chat2Desk API.
+// It constructs the API endpoint URL, sends a DELETE request,
+// and returns the response data as a BasicResponse struct.
+// If an error occurs during the request, it logs the error and returns it.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the tag group to delete.
+//
+// Returns:
+//   - A pointer to a BasicResponse struct containing the response data
+//   - An error if the request fails or if the response is invalid.
+func (dest *Ctd) APIDeleteTagGroup(ctx context.Context, id int64) (*BasicResponse, error) {
+	url := fmt.Sprintf("%sv1/tag_groups/%d", dest.Url, id)
+	response := BasicResponse{}
+	_, err := dest.doRequest(ctx, "DELETE", url, nil, &response)
+	if err != nil {
+		dest.Error(ctx, "Failed to delete tag group: %v", err)
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CreateTag creates a new tag in the Chat2Desk API.
+// It uses the APICreateTag method to create the tag and handles errors.
+// If the response status is not "success", it returns an error.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - payload: The TagPayload containing the details of the tag to be created.
+//
+// Returns:
+//   - A pointer to a Tag, which contains the created tag
+//   - An error if the request fails or if the response is invalid.
+func (dest *Ctd) CreateTag(ctx context.Context, payload *TagPayload) (*Tag, error) {
+	if payload == nil || payload.Label == "" {
+		return nil, ErrorInvalidParameters
+	}
+
+	response, err := dest.APICreateTag(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Status != "success" {
+		dest.Error(ctx, "Failed to create tag: %v", response.Errors)
+		return nil, ErrorInvalidParameters
+	}
+
+	return &response.Data, nil
+}
+
+// UpdateTag updates an existing tag in the Chat2Desk API.
+// It uses the APIUpdateTag method to update the tag and handles errors.
+// Empty fields of the payload are not changed.
+// If the tag is not found, it returns ErrorInvalidTagID.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the tag to update.
+//   - payload: The TagPayload containing the updated details of the tag.
+//
+// Returns:
+//   - A pointer to a Tag, which contains the updated tag
+//   - An error if the request fails or if the response is invalid.
+func (dest *Ctd) UpdateTag(ctx context.Context, id int64, payload *TagPayload) (*Tag, error) {
+	if payload == nil {
+		return nil, ErrorInvalidParameters
+	}
+
+	response, err := dest.APIUpdateTag(ctx, id, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Status != "success" {
+		dest.Error(ctx, "Failed to update tag: %v", response.Errors)
+		if tagNotFound(&response.BasicResponse) {
+			return nil, ErrorInvalidTagID
+		}
+		return nil, ErrorInvalidParameters
+	}
+
+	return &response.Data, nil
+}
+
+// DeleteTag deletes a tag in the Chat2Desk API.
+// It uses the APIDeleteTag method to delete the tag and handles errors.
+// If the tag is not found, it returns ErrorInvalidTagID.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the tag to delete.
+//
+// Returns:
+//   - An error if the request fails or if the response is invalid.
+func (dest *Ctd) DeleteTag(ctx context.Context, id int64) error {
+	response, err := dest.APIDeleteTag(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if response.Status != "success" {
+		dest.Error(ctx, "Failed to delete tag: %v", response.Errors)
+		if tagNotFound(response) {
+			return ErrorInvalidTagID
+		}
+		return ErrorInvalidResponse
+	}
+
+	return nil
+}
+
+// GetTagGroups retrieves a list of tag groups from the Chat2Desk API.
+// It uses the APIGetTagGroups method to fetch the tag groups and handles errors.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//
+// Returns:
+//   - A slice of TagGroup, which contains the tag groups
+//   - An error if the request fails or if the response is invalid.
+func (dest *Ctd) GetTagGroups(ctx context.Context) ([]TagGroup, error) {
+	response, err := dest.APIGetTagGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Status != "success" {
+		dest.Error(ctx, "Failed to get tag groups: %v", response.Errors)
+		return nil, ErrorInvalidResponse
+	}
+
+	return response.Data, nil
+}
+
+// CreateTagGroup creates a new tag group in the Chat2Desk API.
+// It uses the APICreateTagGroup method to create the tag group and handles errors.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - name: The name of the tag group.
+//
+// Returns:
+//   - A pointer to a TagGroup, which contains the created tag group
+//   - An error if the request fails or if the response is invalid.
+func (dest *Ctd) CreateTagGroup(ctx context.Context, name string) (*TagGroup, error) {
+	if name == "" {
+		return nil, ErrorInvalidParameters
+	}
+
+	response, err := dest.APICreateTagGroup(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Status != "success" {
+		dest.Error(ctx, "Failed to create tag group: %v", response.Errors)
+		return nil, ErrorInvalidParameters
+	}
+
+	return &response.Data, nil
+}
+
+// DeleteTagGroup deletes a tag group in the Chat2Desk API.
+// It uses the APIDeleteTagGroup method to delete the tag group and handles errors.
+// If the tag group is not found, it returns ErrorInvalidTagGroupID.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - id: The ID of the tag group to delete.
+//
+// Returns:
+//   - An error if the request fails or if the response is invalid.
+func (dest *Ctd) DeleteTagGroup(ctx context.Context, id int64) error {
+	response, err := dest.APIDeleteTagGroup(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if response.Status != "success" {
+		dest.Error(ctx, "Failed to delete tag group: %v", response.Errors)
+		if tagNotFound(response) {
+			return ErrorInvalidTagGroupID
+		}
+		return ErrorInvalidResponse
+	}
+
+	return nil
+}
+
+// tagNotFound reports whether the unsuccessful response means that the tag doesn't exist.
+func tagNotFound(response *BasicResponse) bool {
+	str := strings.ToLower(fmt.Sprintf("%s %v", response.Message, response.Errors))
+	return strings.Contains(str, "not found") || strings.Contains(str, "not_found") || strings.Contains(str, "does not exist")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/brianvoe/gofakeit/v7"
 	"github.com/ra-company/env"
 	"github.com/stretchr/testify/require"
 )
@@ -263,3 +264,62 @@ func TestCtd_Tags(t *testing.T) {
 		})
 	})
 }
+
+func TestCtd_TagsCRUD(t *testing.T) {
+	ctx := context.Background()
+	faker := gofakeit.New(0)
+
+	url, token := getCredentials(t)
+
+	dst := &Ctd{}
+	dst.Init(url, token)
+
+	var group *TagGroup
+	t.Cleanup(func() {
+		if group != nil {
+			err := dst.DeleteTagGroup(ctx, int64(group.ID))
+			require.NoError(t, err, "dst.DeleteTagGroup() error")
+		}
+	})
+
+	t.Run("01 CreateTagGroup", func(t *testing.T) {
+		var err error
+		group, err = dst.CreateTagGroup(ctx, faker.Word())
+		require.NoError(t, err, "dst.CreateTagGroup() error")
+		require.NotNil(t, group, "dst.CreateTagGroup() should return data")
+
+		groups, err := dst.GetTagGroups(ctx)
+		require.NoError(t, err, "dst.GetTagGroups() error")
+		require.NotEmpty(t, groups, "dst.GetTagGroups() should return data")
+	})
+
+	var tag *Tag
+	t.Run("02 CreateTag", func(t *testing.T) {
+		var err error
+		tag, err = dst.CreateTag(ctx, &TagPayload{GroupID: group.ID, Label: faker.Word(), Description: faker.Sentence(5)})
+		require.NoError(t, err, "dst.CreateTag() error")
+		require.NotNil(t, tag, "dst.CreateTag() should return data")
+		require.Equal(t, group.ID, tag.GroupID, "dst.CreateTag() should return tag in the group")
+
+		_, err = dst.CreateTag(ctx, &TagPayload{GroupID: group.ID})
+		require.ErrorIs(t, err, ErrorInvalidParameters, "dst.CreateTag() error for empty label")
+	})
+
+	t.Run("03 UpdateTag", func(t *testing.T) {
+		label := faker.Word()
+		got, err := dst.UpdateTag(ctx, int64(tag.ID), &TagPayload{Label: label})
+		require.NoError(t, err, "dst.UpdateTag() error")
+		require.Equal(t, label, got.Label, "dst.UpdateTag() should return new label")
+
+		_, err = dst.UpdateTag(ctx, 0, &TagPayload{Label: label})
+		require.ErrorIs(t, err, ErrorInvalidTagID, "dst.UpdateTag() error for invalid tag ID")
+	})
+
+	t.Run("04 DeleteTag", func(t *testing.T) {
+		err := dst.DeleteTag(ctx, int64(tag.ID))
+		require.NoError(t, err, "dst.DeleteTag() error")
+
+		err = dst.DeleteTag(ctx, int64(tag.ID))
+		require.ErrorIs(t, err, ErrorInvalidTagID, "dst.DeleteTag() error for deleted tag")
+	})
+}
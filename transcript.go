@@ -0,0 +1,354 @@
+package ctd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	TranscriptFormatText     = "text"     // Plain text transcript
+	TranscriptFormatMarkdown = "markdown" // Markdown transcript
+	TranscriptFormatHTML     = "html"     // Self-contained HTML transcript
+	TranscriptFormatJSON     = "json"     // JSON export of the transcript
+
+	TranscriptAuthorClient   = "client"   // Line written by the client (message type "in")
+	TranscriptAuthorOperator = "operator" // Line written by an operator (message type "out")
+	TranscriptAuthorSystem   = "system"   // System line (message type "system")
+)
+
+// TranscriptOptions controls how a transcript is built and rendered.
+type TranscriptOptions struct {
+	Location   *time.Location // Location: Timezone used to render message times (default: UTC)
+	TimeLayout string         // TimeLayout: Layout used to render message times (default: "2006-01-02 15:04:05")
+	Title      string         // Title: Optional transcript title
+	ClientName string         // ClientName: Optional name used for client lines (default: "Client")
+	SystemName string         // SystemName: Optional name used for system lines (default: "System")
+}
+
+// TranscriptLine represents a single rendered line of a conversation transcript.
+type TranscriptLine struct {
+	ID         int64     `json:"id"`                    // ID: Message ID
+	Time       time.Time `json:"time"`                  // Time: Message creation time in the transcript timezone
+	Author     string    `json:"author"`                // Author: Line author type ('client', 'operator', 'system')
+	Name       string    `json:"name"`                  // Name: Display name of the author
+	OperatorID int64     `json:"operator_id,omitempty"` // OperatorID: Operator ID for operator lines
+	Format     string    `json:"format"`                // Format: Message format ('text', 'photo', 'video', ...)
+	Text       string    `json:"text"`                  // Text: Message text or media URL
+	Transport  string    `json:"transport,omitempty"`   // Transport: Transport of the message
+}
+
+// Transcript represents a readable conversation built from request messages.
+type Transcript struct {
+	Title     string           `json:"title,omitempty"`      // Title: Transcript title
+	RequestID int64            `json:"request_id,omitempty"` // RequestID: Request ID the transcript was built for
+	DialogID  int64            `json:"dialog_id,omitempty"`  // DialogID: Dialog ID the transcript was built for
+	ClientID  int64            `json:"client_id,omitempty"`  // ClientID: Client ID of the conversation
+	Lines     []TranscriptLine `json:"lines"`                // Lines: Transcript lines in chronological order
+
+	options TranscriptOptions
+}
+
+// NewTranscript builds a transcript from already fetched messages.
+// Messages are expected to be sorted (see RequestMessages). Operator names are
+// resolved using the provided operators; unknown operators are rendered as "Operator #ID".
+//
+// Parameters:
+//   - messages: The messages to include in the transcript.
+//   - operators: The operators used to resolve operator names (may be nil).
+//   - options: The transcript options (may be nil).
+//
+// Returns:
+//   - A pointer to a Transcript containing the rendered lines.
+func NewTranscript(messages []RequestMessage, operators []Operator, options *TranscriptOptions) *Transcript {
+	opts := TranscriptOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	if opts.TimeLayout == "" {
+		opts.TimeLayout = "2006-01-02 15:04:05"
+	}
+	if opts.ClientName == "" {
+		opts.ClientName = "Client"
+	}
+	if opts.SystemName == "" {
+		opts.SystemName = "System"
+	}
+
+	names := make(map[int64]string, len(operators))
+	for _, operator := range operators {
+		names[operator.ID] = strings.TrimSpace(operator.FirstName + " " + operator.LastName)
+	}
+
+	transcript := &Transcript{
+		Title:   opts.Title,
+		Lines:   make([]TranscriptLine, 0, len(messages)),
+		options: opts,
+	}
+
+	for _, message := range messages {
+		if transcript.DialogID == 0 {
+			transcript.DialogID = message.DialogID
+		}
+		if transcript.ClientID == 0 {
+			transcript.ClientID = message.ClientID
+		}
+
+		line := TranscriptLine{
+			ID:        message.ID,
+			Time:      message.CreatedTime().In(opts.Location),
+			Format:    message.MessageFormat(),
+			Text:      message.Message(),
+			Transport: message.Transport,
+		}
+
+		switch message.Type {
+		case "in":
+			line.Author = TranscriptAuthorClient
+			line.Name = opts.ClientName
+		case "out":
+			line.Author = TranscriptAuthorOperator
+			line.OperatorID = message.OperatorID
+			line.Name = names[message.OperatorID]
+			if line.Name == "" {
+				line.Name = fmt.Sprintf("Operator #%d", message.OperatorID)
+			}
+		default:
+			line.Author = TranscriptAuthorSystem
+			line.Name = opts.SystemName
+		}
+
+		transcript.Lines = append(transcript.Lines, line)
+	}
+
+	return transcript
+}
+
+// RequestTranscript builds a transcript for a specific request.
+// It retrieves the messages using RequestMessages and resolves operator names using AllOperators.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - request: The ID of the request for which to build the transcript.
+//   - options: The transcript options (may be nil).
+//
+// Returns:
+//   - A pointer to a Transcript containing the rendered lines.
+//   - An error if the request fails or if the response is invalid.
+func (dst *Ctd) RequestTranscript(ctx context.Context, request int64, options *TranscriptOptions) (*Transcript, error) {
+	messages, err := dst.RequestMessages(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	operators, err := dst.AllOperators(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	transcript := NewTranscript(messages, operators, options)
+	transcript.RequestID = request
+
+	return transcript, nil
+}
+
+// DialogTranscript builds a transcript for the last request of a specific dialog.
+// It retrieves the dialog using GetDialog and builds the transcript of its last request.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - dialog_id: The ID of the dialog for which to build the transcript.
+//   - options: The transcript options (may be nil).
+//
+// Returns:
+//   - A pointer to a Transcript containing the rendered lines.
+//   - An error if the request fails or if the response is invalid.
+func (dst *Ctd) DialogTranscript(ctx context.Context, dialog_id int64, options *TranscriptOptions) (*Transcript, error) {
+	dialog, err := dst.GetDialog(ctx, dialog_id)
+	if err != nil {
+		return nil, err
+	}
+
+	if dialog.LastRequestID == 0 {
+		return nil, ErrorInvalidRequestID
+	}
+
+	transcript, err := dst.RequestTranscript(ctx, dialog.LastRequestID, options)
+	if err != nil {
+		return nil, err
+	}
+	transcript.DialogID = dialog.ID
+
+	return transcript, nil
+}
+
+// Render writes the transcript to the writer in the specified format
+// ('text', 'markdown', 'html' or 'json').
+//
+// Parameters:
+//   - w: The writer to render the transcript to.
+//   - format: The output format.
+//
+// Returns:
+//   - An error if the format is unknown or if writing fails.
+func (dst *Transcript) Render(w io.Writer, format string) error {
+	switch strings.ToLower(format) {
+	case TranscriptFormatText, "":
+		return dst.RenderText(w)
+	case TranscriptFormatMarkdown, "md":
+		return dst.RenderMarkdown(w)
+	case TranscriptFormatHTML:
+		return dst.RenderHTML(w)
+	case TranscriptFormatJSON:
+		return dst.RenderJSON(w)
+	}
+
+	return ErrorInvalidParameters
+}
+
+// RenderText writes the transcript as plain text, one message per line.
+func (dst *Transcript) RenderText(w io.Writer) error {
+	var sb strings.Builder
+
+	if dst.Title != "" {
+		sb.WriteString(dst.Title + "\n\n")
+	}
+
+	for _, line := range dst.Lines {
+		fmt.Fprintf(&sb, "[%s] %s: %s\n", line.Time.Format(dst.layout()), line.Name, line.content())
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// RenderMarkdown writes the transcript as Markdown.
+func (dst *Transcript) RenderMarkdown(w io.Writer) error {
+	var sb strings.Builder
+
+	if dst.Title != "" {
+		sb.WriteString("# " + markdownEscape(dst.Title) + "\n\n")
+	}
+
+	for _, line := range dst.Lines {
+		fmt.Fprintf(&sb, "**%s** _%s_  \n", markdownEscape(line.Name), line.Time.Format(dst.layout()))
+		if line.Format == "text" {
+			for _, text := range strings.Split(line.Text, "\n") {
+				sb.WriteString("> " + markdownEscape(text) + "  \n")
+			}
+		} else if transcriptURL(line.Text) {
+			fmt.Fprintf(&sb, "> [%s](<%s>)\n", markdownEscape(line.Format), markdownURLReplacer.Replace(line.Text))
+		} else {
+			sb.WriteString("> " + markdownEscape(line.content()) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// RenderHTML writes the transcript as a self-contained HTML document with inline styles.
+func (dst *Transcript) RenderHTML(w io.Writer) error {
+	var sb strings.Builder
+
+	title := dst.Title
+	if title == "" {
+		title = "Transcript"
+	}
+
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&sb, "<title>%s</title>\n", html.EscapeString(title))
+	sb.WriteString("<style>\n")
+	sb.WriteString("body{font-family:sans-serif;max-width:800px;margin:20px auto;color:#222}\n")
+	sb.WriteString(".line{margin:8px 0;padding:8px 12px;border-radius:6px}\n")
+	sb.WriteString(".client{background:#eef3fb}\n.operator{background:#eefbf0}\n.system{background:#f4f4f4;color:#666;font-style:italic}\n")
+	sb.WriteString(".meta{font-size:12px;color:#888}\n.text{white-space:pre-wrap}\n")
+	sb.WriteString("</style>\n</head>\n<body>\n")
+	fmt.Fprintf(&sb, "<h1>%s</h1>\n", html.EscapeString(title))
+
+	for _, line := range dst.Lines {
+		fmt.Fprintf(&sb, "<div class=\"line %s\">\n", line.Author)
+		fmt.Fprintf(&sb, "<div class=\"meta\"><b>%s</b> %s</div>\n", html.EscapeString(line.Name), line.Time.Format(dst.layout()))
+		switch {
+		case line.Format == "text" || !transcriptURL(line.Text):
+			// Attachments with URLs other than http and https are written as text
+			fmt.Fprintf(&sb, "<div class=\"text\">%s</div>\n", html.EscapeString(line.content()))
+		case line.Format == "photo":
+			fmt.Fprintf(&sb, "<div><img src=\"%s\" alt=\"photo\" style=\"max-width:100%%\"></div>\n", html.EscapeString(line.Text))
+		default:
+			fmt.Fprintf(&sb, "<div><a href=\"%s\">%s</a></div>\n", html.EscapeString(line.Text), html.EscapeString(line.Format))
+		}
+		sb.WriteString("</div>\n")
+	}
+
+	sb.WriteString("</body>\n</html>\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// RenderJSON writes the transcript as an indented JSON document.
+func (dst *Transcript) RenderJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(dst)
+}
+
+// String returns the transcript rendered as plain text.
+func (dst *Transcript) String() string {
+	var sb strings.Builder
+	_ = dst.RenderText(&sb)
+	return sb.String()
+}
+
+func (dst *Transcript) layout() string {
+	if dst.options.TimeLayout == "" {
+		return "2006-01-02 15:04:05"
+	}
+	return dst.options.TimeLayout
+}
+
+// content returns the line text, prefixing media links with their format.
+func (dst *TranscriptLine) content() string {
+	if dst.Format == "text" {
+		return dst.Text
+	}
+	return fmt.Sprintf("[%s] %s", dst.Format, dst.Text)
+}
+
+// markdownURLReplacer escapes the characters ending a Markdown link destination in angle brackets.
+var markdownURLReplacer = strings.NewReplacer("<", "%3C", ">", "%3E", " ", "%20", "\r", "", "\n", "")
+
+// transcriptURL reports whether the attachment URL is safe to render as a link: only absolute
+// http and https URLs are, so javascript: and data: URLs can't run in the exported transcript.
+func transcriptURL(str string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(str))
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	return scheme == "http" || scheme == "https"
+}
+
+func markdownEscape(str string) string {
+	replacer := strings.NewReplacer(
+		"\\", "\\\\",
+		"*", "\\*",
+		"_", "\\_",
+		"`", "\\`",
+		"[", "\\[",
+		"]", "\\]",
+		"<", "&lt;",
+		">", "&gt;",
+	)
+	return replacer.Replace(str)
+}
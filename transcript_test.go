@@ -0,0 +1,118 @@
+package ctd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranscript_Render(t *testing.T) {
+	location := time.FixedZone("UTC+5", 5*60*60)
+	messages := []RequestMessage{
+		{ID: 1, Text: "Hello <world>", Type: "in", Created: 1700000000, ClientID: 10, DialogID: 20},
+		{ID: 2, Text: "Hi, how can I *help*?", Type: "out", Created: 1700000060, OperatorID: 5, ClientID: 10, DialogID: 20},
+		{ID: 3, Photo: "https://example.com/photo.jpg", Type: "in", Created: 1700000120, ClientID: 10, DialogID: 20},
+		{ID: 4, Text: "Dialog transferred", Type: "system", Created: 1700000180, ClientID: 10, DialogID: 20},
+		{ID: 5, Text: "Bye", Type: "out", Created: 1700000240, OperatorID: 6, ClientID: 10, DialogID: 20},
+	}
+	operators := []Operator{{ID: 5, FirstName: "John", LastName: "Doe"}}
+
+	transcript := NewTranscript(messages, operators, &TranscriptOptions{Location: location, Title: "Request 1"})
+	require.Len(t, transcript.Lines, 5, "NewTranscript() should return all lines")
+	require.Equal(t, int64(20), transcript.DialogID, "NewTranscript() should detect dialog ID")
+	require.Equal(t, int64(10), transcript.ClientID, "NewTranscript() should detect client ID")
+
+	tests := []struct {
+		name     string
+		format   string
+		contains []string
+	}{
+		{
+			name:   "Text",
+			format: TranscriptFormatText,
+			contains: []string{
+				"[2023-11-15 03:13:20] Client: Hello <world>",
+				"John Doe: Hi, how can I *help*?",
+				"Client: [photo] https://example.com/photo.jpg",
+				"System: Dialog transferred",
+				"Operator #6: Bye",
+			},
+		},
+		{
+			name:   "Markdown",
+			format: TranscriptFormatMarkdown,
+			contains: []string{
+				"# Request 1",
+				"> Hi, how can I \\*help\\*?",
+				"> [photo](<https://example.com/photo.jpg>)",
+			},
+		},
+		{
+			name:   "HTML",
+			format: TranscriptFormatHTML,
+			contains: []string{
+				"<!DOCTYPE html>",
+				"Hello &lt;world&gt;",
+				"<img src=\"https://example.com/photo.jpg\"",
+				"class=\"line system\"",
+			},
+		},
+		{
+			name:   "JSON",
+			format: TranscriptFormatJSON,
+			contains: []string{
+				"\"author\": \"operator\"",
+				"\"operator_id\": 5",
+			},
+		},
+	}
+	t.Run("Unsafe URLs", func(t *testing.T) {
+		unsafe := NewTranscript([]RequestMessage{
+			{ID: 1, Photo: "javascript:alert(1)", Type: "in", Created: 1700000000},
+			{ID: 2, Photo: "data:text/html;base64,PHNjcmlwdD4=", Type: "in", Created: 1700000060},
+		}, nil, nil)
+
+		var buf bytes.Buffer
+		require.NoError(t, unsafe.RenderHTML(&buf), "transcript.RenderHTML() error")
+		require.NotContains(t, buf.String(), "<img", "unsafe URLs should not be rendered as images")
+		require.NotContains(t, buf.String(), "href", "unsafe URLs should not be rendered as links")
+		require.Contains(t, buf.String(), "[photo] javascript:alert(1)", "unsafe URLs should be rendered as text")
+
+		buf.Reset()
+		require.NoError(t, unsafe.RenderMarkdown(&buf), "transcript.RenderMarkdown() error")
+		require.NotContains(t, buf.String(), "](", "unsafe URLs should not be rendered as links")
+
+		buf.Reset()
+		spaced := NewTranscript([]RequestMessage{{ID: 1, Photo: "https://example.com/a photo).jpg", Type: "in", Created: 1700000000}}, nil, nil)
+		require.NoError(t, spaced.RenderMarkdown(&buf), "transcript.RenderMarkdown() error")
+		require.Contains(t, buf.String(), "> [photo](<https://example.com/a%20photo).jpg>)", "URL should not break the link")
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := transcript.Render(&buf, tt.format)
+			require.NoError(t, err, "transcript.Render() error")
+			for _, str := range tt.contains {
+				require.Contains(t, buf.String(), str, "transcript.Render() output")
+			}
+		})
+	}
+
+	t.Run("JSON round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, transcript.RenderJSON(&buf), "transcript.RenderJSON() error")
+		got := Transcript{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got), "json.Unmarshal() error")
+		require.Len(t, got.Lines, 5, "decoded transcript should contain all lines")
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		err := transcript.Render(&strings.Builder{}, "pdf")
+		require.ErrorIs(t, err, ErrorInvalidParameters, "transcript.Render() error")
+	})
+}
@@ -0,0 +1,338 @@
+package ctd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var (
+	ErrorUnsupportedByTransport = fmt.Errorf("not supported by transport")
+)
+
+// Capability is a feature of a transport.
+type Capability string
+
+const (
+	CapabilityInlineButtons Capability = "inline_buttons" // Inline buttons under the message
+	CapabilityKeyboard      Capability = "keyboard"       // Reply keyboard
+	CapabilityButtonColors  Capability = "button_colors"  // Colored buttons
+	CapabilityInteractive   Capability = "interactive"    // Interactive lists and buttons
+	CapabilityAttachments   Capability = "attachments"    // Attached files
+	CapabilityPDF           Capability = "pdf"            // PDF documents
+	CapabilityVideo         Capability = "video"          // Video files
+)
+
+// TransportCapabilities describes the message features supported by a transport.
+type TransportCapabilities struct {
+	InlineButtons bool `json:"inline_buttons"`  // InlineButtons: Inline buttons are supported
+	Keyboard      bool `json:"keyboard"`        // Keyboard: Reply keyboard is supported
+	ButtonColors  bool `json:"button_colors"`   // ButtonColors: Button colors are supported
+	Interactive   bool `json:"interactive"`     // Interactive: Interactive lists and buttons are supported
+	Attachments   bool `json:"attachments"`     // Attachments: Attached files are supported
+	PDF           bool `json:"pdf"`             // PDF: PDF documents are supported
+	Video         bool `json:"video"`           // Video: Video files are supported
+	MaxTextLength int  `json:"max_text_length"` // MaxTextLength: Maximum text length in characters (0 - no limit)
+}
+
+// defaultCapabilities holds the capabilities of the known transports, compiled from the Chat2Desk
+// documentation of the message fields and the limits published by the messengers (e.g. 4096 characters
+// in Telegram, 2000 in Facebook and 1000 in Instagram). They may lag behind the platforms and the
+// settings of a company: override them with Ctd.Capabilities, or set Ctd.SkipValidation to send
+// messages unchecked.
+var defaultCapabilities = map[Transport]TransportCapabilities{
+	TransportWhatsApp:    {Attachments: true, PDF: true, Video: true, MaxTextLength: 4096},
+	TransportWABA:        {Keyboard: true, Interactive: true, Attachments: true, PDF: true, Video: true, MaxTextLength: 4096},
+	TransportTelegram:    {InlineButtons: true, Keyboard: true, Attachments: true, PDF: true, Video: true, MaxTextLength: 4096},
+	TransportViber:       {Keyboard: true, Attachments: true, PDF: true, Video: true, MaxTextLength: 7000},
+	TransportViberPublic: {Keyboard: true, Attachments: true, PDF: true, Video: true, MaxTextLength: 7000},
+	TransportVK:          {InlineButtons: true, Keyboard: true, Attachments: true, PDF: true, MaxTextLength: 4096},
+	TransportFacebook:    {InlineButtons: true, Attachments: true, PDF: true, Video: true, MaxTextLength: 2000},
+	TransportInstagram:   {Attachments: true, Video: true, MaxTextLength: 1000},
+	TransportWidget:      {InlineButtons: true, Keyboard: true, ButtonColors: true, Attachments: true, PDF: true, Video: true},
+	TransportSMS:         {},
+	TransportEmail:       {Attachments: true, PDF: true, Video: true},
+	TransportExternal:    {InlineButtons: true, Keyboard: true, ButtonColors: true, Interactive: true, Attachments: true, PDF: true, Video: true},
+}
+
+// videoExtensions lists the file extensions treated as video attachments.
+var videoExtensions = []string{".mp4", ".mov", ".avi", ".mkv", ".webm", ".3gp"}
+
+// DefaultCapabilities is the capability registry used when Ctd.Capabilities is not set.
+var DefaultCapabilities = NewCapabilityRegistry(nil)
+
+// CapabilityRegistry holds the capabilities of the transports. A registry created with a parent
+// holds only the overridden entries and takes the rest from the parent, so a company can
+// adjust a few transports without copying the whole matrix. It is safe for concurrent use.
+type CapabilityRegistry struct {
+	mu     sync.RWMutex
+	parent *CapabilityRegistry
+	items  map[Transport]TransportCapabilities
+}
+
+// NewCapabilityRegistry creates a new CapabilityRegistry.
+//
+// Parameters:
+//   - parent: The registry the entries are inherited from (nil - a registry with the built-in entries).
+//
+// Returns:
+//   - A pointer to a CapabilityRegistry.
+func NewCapabilityRegistry(parent *CapabilityRegistry) *CapabilityRegistry {
+	registry := &CapabilityRegistry{parent: parent, items: map[Transport]TransportCapabilities{}}
+	if parent == nil {
+		maps.Copy(registry.items, defaultCapabilities)
+	}
+	return registry
+}
+
+// Get returns the capabilities of the transport.
+//
+// Parameters:
+//   - transport: The transport.
+//
+// Returns:
+//   - The capabilities of the transport.
+//   - false if the transport is unknown.
+func (dst *CapabilityRegistry) Get(transport Transport) (TransportCapabilities, bool) {
+	dst.mu.RLock()
+	capabilities, ok := dst.items[transport]
+	dst.mu.RUnlock()
+
+	if !ok && dst.parent != nil {
+		return dst.parent.Get(transport)
+	}
+	return capabilities, ok
+}
+
+// Set overrides the capabilities of the transport.
+//
+// Parameters:
+//   - transport: The transport.
+//   - capabilities: The capabilities.
+func (dst *CapabilityRegistry) Set(transport Transport, capabilities TransportCapabilities) {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	dst.items[transport] = capabilities
+}
+
+// Reset removes the entry of the transport, restoring the entry of the parent if any.
+//
+// Parameters:
+//   - transport: The transport.
+func (dst *CapabilityRegistry) Reset(transport Transport) {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	delete(dst.items, transport)
+}
+
+// All returns the capabilities of all the transports, including the inherited ones.
+//
+// Returns:
+//   - A map of TransportCapabilities by transport.
+func (dst *CapabilityRegistry) All() map[Transport]TransportCapabilities {
+	result := map[Transport]TransportCapabilities{}
+	if dst.parent != nil {
+		result = dst.parent.All()
+	}
+
+	dst.mu.RLock()
+	defer dst.mu.RUnlock()
+
+	maps.Copy(result, dst.items)
+	return result
+}
+
+// Has reports whether the capability is supported.
+func (dst TransportCapabilities) Has(capability Capability) bool {
+	switch capability {
+	case CapabilityInlineButtons:
+		return dst.InlineButtons
+	case CapabilityKeyboard:
+		return dst.Keyboard
+	case CapabilityButtonColors:
+		return dst.ButtonColors
+	case CapabilityInteractive:
+		return dst.Interactive
+	case CapabilityAttachments:
+		return dst.Attachments
+	case CapabilityPDF:
+		return dst.PDF
+	case CapabilityVideo:
+		return dst.Video
+	}
+	return false
+}
+
+// Validate checks that the message uses only the supported features.
+// The type of an attachment is detected by the extension of its file name or URL.
+//
+// Parameters:
+//   - message: The message payload.
+//
+// Returns:
+//   - ErrorUnsupportedByTransport wrapped with the name of the first unsupported feature.
+func (dst TransportCapabilities) Validate(message *MessagePayload) error {
+	unsupported := func(feature string) error {
+		return fmt.Errorf("%w: %s", ErrorUnsupportedByTransport, feature)
+	}
+
+	if len(message.InlineButtons) > 0 && !dst.InlineButtons {
+		return unsupported(string(CapabilityInlineButtons))
+	}
+	if message.Keyboard != nil && len(message.Keyboard.Buttons) > 0 && !dst.Keyboard {
+		return unsupported(string(CapabilityKeyboard))
+	}
+	if !dst.ButtonColors {
+		buttons := slices.Clone(message.InlineButtons)
+		if message.Keyboard != nil {
+			buttons = append(buttons, message.Keyboard.Buttons...)
+		}
+		if slices.ContainsFunc(buttons, func(button MessageButton) bool { return button.Color != "" }) {
+			return unsupported(string(CapabilityButtonColors))
+		}
+	}
+	if message.Interactive != "" && !dst.Interactive {
+		return unsupported(string(CapabilityInteractive))
+	}
+
+	if message.Attachment != "" {
+		if !dst.Attachments {
+			return unsupported(string(CapabilityAttachments))
+		}
+		name := message.AttachmentFilename
+		if name == "" {
+			name, _, _ = strings.Cut(message.Attachment, "?")
+		}
+		extension := strings.ToLower(path.Ext(name))
+		if extension == ".pdf" && !dst.PDF {
+			return unsupported(string(CapabilityPDF))
+		}
+		if slices.Contains(videoExtensions, extension) && !dst.Video {
+			return unsupported(string(CapabilityVideo))
+		}
+	}
+
+	if dst.MaxTextLength > 0 && utf8.RuneCountInString(message.Text) > dst.MaxTextLength {
+		return unsupported(fmt.Sprintf("text longer than %d characters", dst.MaxTextLength))
+	}
+
+	return nil
+}
+
+// GetTransportCapabilities returns the capabilities of the transport for the company,
+// taken from Ctd.Capabilities or DefaultCapabilities.
+//
+// Parameters:
+//   - transport: The transport.
+//
+// Returns:
+//   - The capabilities of the transport.
+//   - false if the transport is unknown.
+func (dst *Ctd) GetTransportCapabilities(transport Transport) (TransportCapabilities, bool) {
+	return dst.capabilities().Get(transport)
+}
+
+// ValidateMessage checks the message against the capabilities of its transport.
+// Messages without a transport or with an unknown transport are not checked.
+//
+// Parameters:
+//   - message: The message payload.
+//
+// Returns:
+//   - ErrorUnsupportedByTransport if the message uses an unsupported feature.
+func (dst *Ctd) ValidateMessage(message *MessagePayload) error {
+	if message.Transport == "" {
+		return nil
+	}
+
+	capabilities, ok := dst.GetTransportCapabilities(Transport(strings.ToLower(message.Transport)))
+	if !ok {
+		return nil
+	}
+
+	return capabilities.Validate(message)
+}
+
+// TransportsWith returns the transports of the channel supporting the capability.
+//
+// Parameters:
+//   - registry: The capability registry (nil - DefaultCapabilities).
+//   - capability: The capability.
+//
+// Returns:
+//   - A slice of Transport.
+func (dst *Channel) TransportsWith(registry *CapabilityRegistry, capability Capability) []Transport {
+	if registry == nil {
+		registry = DefaultCapabilities
+	}
+
+	result := []Transport{}
+	for _, transport := range dst.GetTransports() {
+		if capabilities, ok := registry.Get(transport); ok && capabilities.Has(capability) {
+			result = append(result, transport)
+		}
+	}
+	return result
+}
+
+// HasCapability reports whether a transport of the channel supports the capability.
+//
+// Parameters:
+//   - registry: The capability registry (nil - DefaultCapabilities).
+//   - capability: The capability.
+func (dst *Channel) HasCapability(registry *CapabilityRegistry, capability Capability) bool {
+	return len(dst.TransportsWith(registry, capability)) > 0
+}
+
+// FilterChannelsWith returns the channels having a transport that supports the capability.
+//
+// Parameters:
+//   - channels: The channels.
+//   - registry: The capability registry (nil - DefaultCapabilities).
+//   - capability: The capability.
+//
+// Returns:
+//   - A slice of Channel supporting the capability.
+func FilterChannelsWith(channels []Channel, registry *CapabilityRegistry, capability Capability) []Channel {
+	result := []Channel{}
+	for i := range channels {
+		if channels[i].HasCapability(registry, capability) {
+			result = append(result, channels[i])
+		}
+	}
+	return result
+}
+
+// ChannelsWithCapability retrieves all channels having a transport that supports the capability
+// according to Ctd.Capabilities or DefaultCapabilities.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - capability: The capability.
+//
+// Returns:
+//   - A slice of Channel.
+//   - An error if the request fails.
+func (dst *Ctd) ChannelsWithCapability(ctx context.Context, capability Capability) ([]Channel, error) {
+	channels, err := dst.AllChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return FilterChannelsWith(channels, dst.capabilities(), capability), nil
+}
+
+// capabilities returns the capability registry of the company.
+func (dst *Ctd) capabilities() *CapabilityRegistry {
+	if dst.Capabilities == nil {
+		return DefaultCapabilities
+	}
+	return dst.Capabilities
+}
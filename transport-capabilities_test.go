@@ -0,0 +1,123 @@
+package ctd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportCapabilities_Validate(t *testing.T) {
+	buttons := []MessageButton{{Type: "reply", Text: "Yes"}}
+
+	tests := []struct {
+		name      string
+		transport Transport
+		message   MessagePayload
+		error     bool
+	}{
+		{"Plain text", TransportSMS, MessagePayload{Text: "Hello"}, false},
+		{"Inline buttons in Telegram", TransportTelegram, MessagePayload{Text: "Hello", InlineButtons: buttons}, false},
+		{"Inline buttons in WhatsApp", TransportWhatsApp, MessagePayload{Text: "Hello", InlineButtons: buttons}, true},
+		{"Keyboard in Viber", TransportViber, MessagePayload{Keyboard: &MessageButtons{Buttons: buttons}}, false},
+		{"Keyboard in Instagram", TransportInstagram, MessagePayload{Keyboard: &MessageButtons{Buttons: buttons}}, true},
+		{"Colors in widget", TransportWidget, MessagePayload{InlineButtons: []MessageButton{{Text: "Yes", Color: "green"}}}, false},
+		{"Colors in Telegram", TransportTelegram, MessagePayload{InlineButtons: []MessageButton{{Text: "Yes", Color: "green"}}}, true},
+		{"Interactive in wa_dialog", TransportWABA, MessagePayload{Interactive: `{"type":"list"}`}, false},
+		{"Interactive in WhatsApp", TransportWhatsApp, MessagePayload{Interactive: `{"type":"list"}`}, true},
+		{"Attachment in SMS", TransportSMS, MessagePayload{Attachment: "https://example.com/photo.jpg"}, true},
+		{"PDF in Instagram", TransportInstagram, MessagePayload{Attachment: "https://example.com/file.PDF?token=1"}, true},
+		{"PDF by file name", TransportInstagram, MessagePayload{Attachment: "https://example.com/file", AttachmentFilename: "invoice.pdf"}, true},
+		{"Video in VK", TransportVK, MessagePayload{Attachment: "https://example.com/clip.mp4"}, true},
+		{"Photo in VK", TransportVK, MessagePayload{Attachment: "https://example.com/photo.png"}, false},
+		{"Long text in Instagram", TransportInstagram, MessagePayload{Text: strings.Repeat("я", 1001)}, true},
+		{"Limit text in Instagram", TransportInstagram, MessagePayload{Text: strings.Repeat("я", 1000)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capabilities, ok := DefaultCapabilities.Get(tt.transport)
+			require.True(t, ok, "DefaultCapabilities.Get() should know the transport")
+			err := capabilities.Validate(&tt.message)
+			if tt.error {
+				require.ErrorIs(t, err, ErrorUnsupportedByTransport, "capabilities.Validate() should reject the message")
+			} else {
+				require.NoError(t, err, "capabilities.Validate() error")
+			}
+		})
+	}
+}
+
+func TestCapabilityRegistry(t *testing.T) {
+	registry := NewCapabilityRegistry(DefaultCapabilities)
+	registry.Set(TransportWhatsApp, TransportCapabilities{InlineButtons: true})
+
+	capabilities, ok := registry.Get(TransportWhatsApp)
+	require.True(t, ok, "registry.Get() should find the override")
+	require.True(t, capabilities.Has(CapabilityInlineButtons), "override should be used")
+	capabilities, _ = DefaultCapabilities.Get(TransportWhatsApp)
+	require.False(t, capabilities.Has(CapabilityInlineButtons), "the parent should not change")
+
+	capabilities, ok = registry.Get(TransportTelegram)
+	require.True(t, ok, "registry.Get() should inherit the entries")
+	require.True(t, capabilities.Has(CapabilityKeyboard), "inherited entry")
+
+	_, ok = registry.Get("pigeon")
+	require.False(t, ok, "unknown transport")
+	require.Len(t, registry.All(), len(AllTransports()), "registry.All() should merge the entries")
+
+	registry.Reset(TransportWhatsApp)
+	capabilities, _ = registry.Get(TransportWhatsApp)
+	require.False(t, capabilities.Has(CapabilityInlineButtons), "registry.Reset() should restore the parent entry")
+
+	channel := Channel{ID: 1, Transports: []string{"whatsapp", "telegram", "widget"}}
+	require.Equal(t, []Transport{TransportWidget}, channel.TransportsWith(nil, CapabilityButtonColors), "channel.TransportsWith()")
+	require.True(t, channel.HasCapability(nil, CapabilityInlineButtons), "channel.HasCapability()")
+
+	channels := []Channel{channel, {ID: 2, Transports: []string{"whatsapp", "sms"}}}
+	require.Len(t, FilterChannelsWith(channels, nil, CapabilityInlineButtons), 1, "FilterChannelsWith() should skip channels without the capability")
+	registry.Set(TransportWhatsApp, TransportCapabilities{InlineButtons: true})
+	require.Len(t, FilterChannelsWith(channels, registry, CapabilityInlineButtons), 2, "FilterChannelsWith() should use the registry")
+}
+
+func TestCtd_ValidateMessage(t *testing.T) {
+	ctx := context.Background()
+
+	requests := atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"status":"success","data":{"message_id":1}}`))
+	}))
+	defer server.Close()
+
+	dst := &Ctd{}
+	dst.Init(server.URL, "token")
+
+	message := &MessagePayload{Text: "Hello", ClientID: 1, Transport: "WhatsApp", InlineButtons: []MessageButton{{Type: "reply", Text: "Yes"}}}
+	require.ErrorIs(t, dst.ValidateMessage(message), ErrorUnsupportedByTransport, "dst.ValidateMessage() should use the default capabilities")
+	_, err := dst.SendMessage(ctx, message)
+	require.ErrorIs(t, err, ErrorUnsupportedByTransport, "dst.SendMessage() should validate the message by default")
+	require.Equal(t, int32(0), requests.Load(), "invalid message should not be sent")
+
+	dst.SkipValidation = true
+	_, err = dst.SendMessage(ctx, message)
+	require.NoError(t, err, "messages should not be validated with SkipValidation")
+	require.Equal(t, int32(1), requests.Load(), "message should be sent")
+
+	dst.SkipValidation = false
+	dst.Capabilities = NewCapabilityRegistry(DefaultCapabilities)
+	_, err = dst.SendMessage(ctx, message)
+	require.ErrorIs(t, err, ErrorUnsupportedByTransport, "dst.SendMessage() should validate the message")
+	require.Equal(t, int32(1), requests.Load(), "invalid message should not be sent")
+
+	dst.Capabilities.Set(TransportWhatsApp, TransportCapabilities{InlineButtons: true})
+	_, err = dst.SendMessage(ctx, message)
+	require.NoError(t, err, "company override should allow the message")
+	require.Equal(t, int32(2), requests.Load(), "valid message should be sent")
+
+	_, err = dst.SendMessage(ctx, &MessagePayload{Text: "Hello", ClientID: 1, Transport: "custom", InlineButtons: message.InlineButtons})
+	require.NoError(t, err, "unknown transports should not be checked")
+}
@@ -0,0 +1,69 @@
+package ctd
+
+import (
+	"slices"
+	"strings"
+)
+
+// Transport is a messaging transport of the Chat2Desk channels.
+// The constants are the transport names listed in the Chat2Desk API documentation of the channels and
+// the clients. The list is not checked against the API, which remains the source of truth: it rejects
+// a transport it does not support with an "incorrect transport" error (see CreateClient). Transports
+// missing from the list are passed through unchanged.
+type Transport string
+
+const (
+	TransportWhatsApp    Transport = "whatsapp"     // WhatsApp
+	TransportWABA        Transport = "wa_dialog"    // WhatsApp Business API
+	TransportTelegram    Transport = "telegram"     // Telegram
+	TransportViber       Transport = "viber"        // Viber
+	TransportViberPublic Transport = "viber_public" // Viber public accounts
+	TransportVK          Transport = "vkontakte"    // VK
+	TransportFacebook    Transport = "facebook"     // Facebook Messenger
+	TransportInstagram   Transport = "instagram"    // Instagram
+	TransportWidget      Transport = "widget"       // Online chat on the website
+	TransportSMS         Transport = "sms"          // SMS
+	TransportEmail       Transport = "email"        // Email
+	TransportExternal    Transport = "external"     // External channels connected through the API
+)
+
+// transports lists the documented transports in the order of AllTransports.
+var transports = []Transport{
+	TransportWhatsApp, TransportWABA, TransportTelegram, TransportViber, TransportViberPublic, TransportVK,
+	TransportFacebook, TransportInstagram, TransportWidget, TransportSMS, TransportEmail, TransportExternal,
+}
+
+// AllTransports returns the documented transports.
+//
+// Returns:
+//   - A slice of Transport.
+func AllTransports() []Transport {
+	return slices.Clone(transports)
+}
+
+// ParseTransport converts a string to a Transport, ignoring case and surrounding spaces.
+// Transports missing from AllTransports are returned as is.
+//
+// Parameters:
+//   - str: The transport name.
+//
+// Returns:
+//   - The Transport.
+//   - ErrorInvalidTransport if the name is empty.
+func ParseTransport(str string) (Transport, error) {
+	transport := Transport(strings.ToLower(strings.TrimSpace(str)))
+	if transport == "" {
+		return "", ErrorInvalidTransport
+	}
+	return transport, nil
+}
+
+// IsValid reports whether the transport is one of AllTransports.
+func (dst Transport) IsValid() bool {
+	return slices.Contains(transports, dst)
+}
+
+// String returns the transport name used by the API.
+func (dst Transport) String() string {
+	return string(dst)
+}
@@ -0,0 +1,86 @@
+package ctd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTransport(t *testing.T) {
+	transport, err := ParseTransport(" Telegram ")
+	require.NoError(t, err, "ParseTransport() error")
+	require.Equal(t, TransportTelegram, transport, "ParseTransport() should ignore case and spaces")
+
+	transport, err = ParseTransport("Pigeon")
+	require.NoError(t, err, "ParseTransport() error")
+	require.Equal(t, Transport("pigeon"), transport, "unknown transport should be passed through")
+	require.False(t, transport.IsValid(), "unknown transport should not be listed")
+
+	_, err = ParseTransport(" ")
+	require.ErrorIs(t, err, ErrorInvalidTransport, "empty transport should be rejected")
+
+	all := AllTransports()
+	require.Contains(t, all, TransportWABA, "AllTransports() should contain wa_dialog")
+	all[0] = "changed"
+	require.True(t, TransportWhatsApp.IsValid(), "AllTransports() should return a copy")
+}
+
+func TestChannelsForTransport(t *testing.T) {
+	ctx := context.Background()
+
+	channels := []Channel{}
+	for i := range 150 {
+		channel := Channel{ID: i + 1, Transports: []string{"widget"}}
+		if i%50 == 49 {
+			channel.Transports = append(channel.Transports, "telegram", "unknown")
+		}
+		channels = append(channels, channel)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		end := min(offset+limit, len(channels))
+		json.NewEncoder(w).Encode(map[string]any{
+			"status": "success",
+			"data":   channels[offset:end],
+			"meta":   map[string]any{"total": len(channels), "offset": offset, "limit": limit},
+		})
+	}))
+	defer server.Close()
+
+	dst := &Ctd{}
+	dst.Init(server.URL, "token")
+
+	all, err := dst.AllChannels(ctx)
+	require.NoError(t, err, "dst.AllChannels() error")
+	require.Len(t, all, 150, "dst.AllChannels() should walk all the pages")
+
+	channel, err := dst.GetChannel(ctx, 150)
+	require.NoError(t, err, "dst.GetChannel() error")
+	require.Equal(t, []Transport{TransportWidget, TransportTelegram, "unknown"}, channel.GetTransports(), "unknown transports should be kept")
+
+	_, err = dst.GetChannel(ctx, 151)
+	require.ErrorIs(t, err, ErrorInvalidChannelID, "missing channel should be reported")
+
+	telegram, err := dst.ChannelsForTransport(ctx, TransportTelegram)
+	require.NoError(t, err, "dst.ChannelsForTransport() error")
+	require.Len(t, telegram, 3, "channels supporting telegram")
+
+	id, err := dst.ChannelIDForTransport(ctx, TransportTelegram)
+	require.NoError(t, err, "dst.ChannelIDForTransport() error")
+	require.Equal(t, 50, id, "first channel supporting telegram")
+
+	_, err = dst.ChannelIDForTransport(ctx, TransportEmail)
+	require.ErrorIs(t, err, ErrorInvalidTransport, "no channel supports email")
+	unknown, err := dst.ChannelsForTransport(ctx, "unknown")
+	require.NoError(t, err, "dst.ChannelsForTransport() error")
+	require.Len(t, unknown, 3, "unknown transports should be passed through")
+	_, err = dst.ChannelsForTransport(ctx, "")
+	require.ErrorIs(t, err, ErrorInvalidTransport, "empty transport should be rejected")
+}
@@ -0,0 +1,240 @@
+package ctd
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ra-company/logging"
+)
+
+var (
+	ErrorDispatcherFull   = fmt.Errorf("dispatcher queue is full")
+	ErrorDispatcherClosed = fmt.Errorf("dispatcher is closed")
+)
+
+// WebhookDispatcherOptions controls the WebhookDispatcher.
+type WebhookDispatcherOptions struct {
+	Workers   int           // Workers: Number of concurrent workers (default: 8)
+	QueueSize int           // QueueSize: Capacity of the queue of every worker (default: 100)
+	Block     bool          // Block: Wait for free space in the queue instead of returning ErrorDispatcherFull
+	DedupTTL  time.Duration // DedupTTL: How long the event keys are remembered (default: 24 hours)
+	Store     SeenStore     // Store: Store of the seen event keys (default: MemorySeenStore)
+
+	OnError func(ctx context.Context, notification *WebhookNotification, err error) // OnError: Optional callback called when the handler fails or panics
+}
+
+type dispatcherItem struct {
+	ctx          context.Context
+	notification *WebhookNotification
+}
+
+// WebhookDispatcher deduplicates webhook notifications and passes them to the handler.
+// Notifications of the same client (or of the same dialog, if there is no client) are processed
+// one by one in the order they were dispatched; notifications of different clients are processed concurrently.
+// Notifications are distributed between the workers by the hash of the client ID, so every worker
+// has its own queue. When the queue is full, Dispatch returns ErrorDispatcherFull or waits, if Block is set.
+//
+// The dispatcher must not share the seen store with the WebhookVerifier, otherwise all notifications are dropped as duplicates.
+type WebhookDispatcher struct {
+	options WebhookDispatcherOptions
+	handler func(ctx context.Context, notification *WebhookNotification) error
+
+	mu      sync.RWMutex
+	closed  bool
+	closing chan struct{} // closing: Closed by Shutdown to wake up the blocked Dispatch calls
+	once    sync.Once
+	queues  []chan dispatcherItem
+	wg      sync.WaitGroup
+}
+
+// NewWebhookDispatcher creates a new WebhookDispatcher and starts its workers.
+//
+// Parameters:
+//   - handler: The function processing the notifications.
+//   - options: The dispatcher options (may be nil).
+//
+// Returns:
+//   - A pointer to a WebhookDispatcher.
+func NewWebhookDispatcher(handler func(ctx context.Context, notification *WebhookNotification) error, options *WebhookDispatcherOptions) *WebhookDispatcher {
+	opts := WebhookDispatcherOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 8
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 100
+	}
+	if opts.DedupTTL <= 0 {
+		opts.DedupTTL = 24 * time.Hour
+	}
+	if opts.Store == nil {
+		opts.Store = NewMemorySeenStore()
+	}
+
+	dispatcher := &WebhookDispatcher{
+		options: opts,
+		handler: handler,
+		closing: make(chan struct{}),
+		queues:  make([]chan dispatcherItem, opts.Workers),
+	}
+
+	for i := range dispatcher.queues {
+		queue := make(chan dispatcherItem, opts.QueueSize)
+		dispatcher.queues[i] = queue
+		dispatcher.wg.Add(1)
+		go dispatcher.work(queue)
+	}
+
+	return dispatcher
+}
+
+// Dispatch queues the notification for processing.
+// The handler gets a context that keeps the values of ctx but is not canceled with it,
+// so it can be the context of an HTTP request.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - notification: The webhook notification.
+//
+// Returns:
+//   - ErrorWebhookReplayed if the notification was already dispatched, ErrorDispatcherFull if the queue is full,
+//     ErrorDispatcherClosed if the dispatcher is shut down (also while waiting in Block mode), or the store error.
+func (dst *WebhookDispatcher) Dispatch(ctx context.Context, notification *WebhookNotification) error {
+	dst.mu.RLock()
+	defer dst.mu.RUnlock()
+
+	if dst.closed {
+		return ErrorDispatcherClosed
+	}
+
+	key := notification.Key()
+	seen, err := dst.options.Store.Seen(ctx, key, dst.options.DedupTTL)
+	if err != nil {
+		return err
+	}
+	if seen {
+		return ErrorWebhookReplayed
+	}
+
+	item := dispatcherItem{ctx: context.WithoutCancel(ctx), notification: notification}
+	queue := dst.queues[dispatcherPartition(notification, len(dst.queues))]
+
+	if dst.options.Block {
+		select {
+		case queue <- item:
+			return nil
+		case <-ctx.Done():
+			dst.forget(ctx, key)
+			return ctx.Err()
+		case <-dst.closing:
+			dst.forget(ctx, key)
+			return ErrorDispatcherClosed
+		}
+	}
+
+	select {
+	case queue <- item:
+		return nil
+	default:
+		dst.forget(ctx, key)
+		return ErrorDispatcherFull
+	}
+}
+
+// forget removes the key of a notification that wasn't queued, so its repeated request is accepted.
+func (dst *WebhookDispatcher) forget(ctx context.Context, key string) {
+	if err := dst.options.Store.Forget(context.WithoutCancel(ctx), key); err != nil {
+		logging.Logs.Errorf(ctx, "Failed to forget webhook event %s: %v", key, err)
+	}
+}
+
+// Pending returns the number of queued notifications that are not processed yet.
+func (dst *WebhookDispatcher) Pending() int {
+	dst.mu.RLock()
+	defer dst.mu.RUnlock()
+
+	pending := 0
+	for _, queue := range dst.queues {
+		pending += len(queue)
+	}
+	return pending
+}
+
+// Shutdown stops accepting new notifications and waits until the queued ones are processed.
+//
+// Parameters:
+//   - ctx: The context limiting the waiting time.
+//
+// Returns:
+//   - The context error if the context is canceled before all the notifications are processed.
+func (dst *WebhookDispatcher) Shutdown(ctx context.Context) error {
+	// Wake up the Dispatch calls waiting for queue space, so they release the read lock
+	dst.once.Do(func() { close(dst.closing) })
+
+	dst.mu.Lock()
+	if !dst.closed {
+		dst.closed = true
+		for _, queue := range dst.queues {
+			close(queue)
+		}
+	}
+	dst.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		dst.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// work processes the notifications of a queue one by one.
+func (dst *WebhookDispatcher) work(queue chan dispatcherItem) {
+	defer dst.wg.Done()
+
+	for item := range queue {
+		if err := dst.handle(item); err != nil && dst.options.OnError != nil {
+			dst.options.OnError(item.ctx, item.notification, err)
+		}
+	}
+}
+
+// handle calls the handler and converts its panic to an error.
+func (dst *WebhookDispatcher) handle(item dispatcherItem) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("webhook handler panic: %v", r)
+		}
+	}()
+
+	return dst.handler(item.ctx, item.notification)
+}
+
+// dispatcherPartition returns the worker index for the notification.
+func dispatcherPartition(notification *WebhookNotification, workers int) int {
+	var key string
+	switch {
+	case notification.ClientID != 0:
+		key = "c" + strconv.FormatInt(notification.ClientID, 10)
+	case notification.DialogID != 0:
+		key = "d" + strconv.FormatInt(notification.DialogID, 10)
+	default:
+		key = notification.Key()
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return int(hash.Sum32() % uint32(workers))
+}
@@ -0,0 +1,121 @@
+package ctd
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDispatcher(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Deduplication and ordering", func(t *testing.T) {
+		mu := sync.Mutex{}
+		processed := map[int64][]int64{}
+		active := map[int64]bool{}
+		overlapped := false
+
+		dispatcher := NewWebhookDispatcher(func(ctx context.Context, notification *WebhookNotification) error {
+			mu.Lock()
+			if active[notification.DialogID] {
+				overlapped = true
+			}
+			active[notification.DialogID] = true
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			active[notification.DialogID] = false
+			processed[notification.DialogID] = append(processed[notification.DialogID], notification.MessageID)
+			mu.Unlock()
+			return nil
+		}, &WebhookDispatcherOptions{Workers: 4})
+
+		for i := int64(1); i <= 20; i++ {
+			notification := &WebhookNotification{HookType: "inbox", MessageID: i, DialogID: i%3 + 1}
+			require.NoError(t, dispatcher.Dispatch(ctx, notification), "dispatcher.Dispatch() error")
+		}
+		err := dispatcher.Dispatch(ctx, &WebhookNotification{HookType: "inbox", MessageID: 5, DialogID: 3})
+		require.ErrorIs(t, err, ErrorWebhookReplayed, "duplicate should be dropped")
+
+		require.NoError(t, dispatcher.Shutdown(ctx), "dispatcher.Shutdown() error")
+		require.False(t, overlapped, "notifications of the same dialog should not be processed concurrently")
+		require.Equal(t, []int64{3, 6, 9, 12, 15, 18}, processed[1], "notifications of the same dialog should keep the order")
+		require.Len(t, append(processed[2], processed[3]...), 14, "all notifications should be processed")
+
+		err = dispatcher.Dispatch(ctx, &WebhookNotification{HookType: "inbox", MessageID: 100})
+		require.ErrorIs(t, err, ErrorDispatcherClosed, "closed dispatcher should reject notifications")
+	})
+
+	t.Run("Backpressure", func(t *testing.T) {
+		release := make(chan struct{})
+		processed := atomic.Int32{}
+		dispatcher := NewWebhookDispatcher(func(ctx context.Context, notification *WebhookNotification) error {
+			<-release
+			processed.Add(1)
+			return nil
+		}, &WebhookDispatcherOptions{Workers: 1, QueueSize: 1})
+
+		require.NoError(t, dispatcher.Dispatch(ctx, &WebhookNotification{MessageID: 1}), "first notification is taken by the worker")
+		require.Eventually(t, func() bool { return dispatcher.Pending() == 0 }, time.Second, time.Millisecond, "worker should take the first notification")
+		require.NoError(t, dispatcher.Dispatch(ctx, &WebhookNotification{MessageID: 2}), "second notification is queued")
+		require.ErrorIs(t, dispatcher.Dispatch(ctx, &WebhookNotification{MessageID: 3}), ErrorDispatcherFull, "queue should be full")
+
+		close(release)
+		require.NoError(t, dispatcher.Shutdown(ctx), "dispatcher.Shutdown() error")
+		require.Equal(t, int32(2), processed.Load(), "queued notifications should be processed on shutdown")
+
+		// Rejected notification can be dispatched again
+		dispatcher = NewWebhookDispatcher(func(ctx context.Context, notification *WebhookNotification) error { return nil }, nil)
+		require.NoError(t, dispatcher.Dispatch(ctx, &WebhookNotification{MessageID: 3}), "rejected notification should not be marked as seen")
+		require.NoError(t, dispatcher.Shutdown(ctx), "dispatcher.Shutdown() error")
+	})
+
+	t.Run("Shutdown while blocked", func(t *testing.T) {
+		release := make(chan struct{})
+		dispatcher := NewWebhookDispatcher(func(ctx context.Context, notification *WebhookNotification) error {
+			<-release
+			return nil
+		}, &WebhookDispatcherOptions{Workers: 1, QueueSize: 1, Block: true})
+
+		require.NoError(t, dispatcher.Dispatch(ctx, &WebhookNotification{MessageID: 1}), "first notification is taken by the worker")
+		require.Eventually(t, func() bool { return dispatcher.Pending() == 0 }, time.Second, time.Millisecond, "worker should take the first notification")
+		require.NoError(t, dispatcher.Dispatch(ctx, &WebhookNotification{MessageID: 2}), "second notification is queued")
+
+		blocked := make(chan error, 1)
+		go func() { blocked <- dispatcher.Dispatch(ctx, &WebhookNotification{MessageID: 3}) }()
+		time.Sleep(10 * time.Millisecond)
+
+		stopped := make(chan error, 1)
+		go func() { stopped <- dispatcher.Shutdown(ctx) }()
+		require.ErrorIs(t, <-blocked, ErrorDispatcherClosed, "blocked Dispatch should return on shutdown")
+
+		close(release)
+		require.NoError(t, <-stopped, "dispatcher.Shutdown() error")
+	})
+
+	t.Run("Client partition", func(t *testing.T) {
+		first := dispatcherPartition(&WebhookNotification{ClientID: 7}, 64)
+		for dialog := int64(1); dialog <= 10; dialog++ {
+			require.Equal(t, first, dispatcherPartition(&WebhookNotification{ClientID: 7, DialogID: dialog}, 64), "events of the client should go to the same worker")
+		}
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		failed := make(chan error, 1)
+		dispatcher := NewWebhookDispatcher(func(ctx context.Context, notification *WebhookNotification) error {
+			panic("boom")
+		}, &WebhookDispatcherOptions{OnError: func(ctx context.Context, notification *WebhookNotification, err error) {
+			failed <- err
+		}})
+
+		require.NoError(t, dispatcher.Dispatch(ctx, &WebhookNotification{MessageID: 1}), "dispatcher.Dispatch() error")
+		require.NoError(t, dispatcher.Shutdown(ctx), "dispatcher.Shutdown() error")
+		require.ErrorContains(t, <-failed, "boom", "panic should be reported as error")
+	})
+}
@@ -0,0 +1,369 @@
+package ctd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	WebhookEventError     = "error"     // New delivery errors of a webhook
+	WebhookEventDisabled  = "disabled"  // Webhook was disabled
+	WebhookEventEnabled   = "enabled"   // Webhook was enabled again
+	WebhookEventReenabled = "reenabled" // Webhook was re-enabled by the monitor (Error is set if it failed)
+)
+
+// WebhookEvent describes a change of a webhook health detected by the WebhookMonitor.
+type WebhookEvent struct {
+	Type    string          // Type: Event type ('error', 'disabled', 'enabled', 'reenabled')
+	Webhook Webhook         // Webhook: Current state of the webhook
+	Errors  []WebhookErrors // Errors: New delivery errors ('error' only)
+	Error   error           // Error: Health check or update error ('reenabled' only)
+	Time    time.Time       // Time: Time when the change was detected
+}
+
+// WebhookMetrics contains the counters of the WebhookMonitor.
+type WebhookMetrics struct {
+	Polls            uint64 // Polls: Number of successful polls
+	PollErrors       uint64 // PollErrors: Number of failed polls
+	Webhooks         int    // Webhooks: Number of webhooks after the last poll
+	Disabled         int    // Disabled: Number of disabled webhooks after the last poll
+	Errors           uint64 // Errors: Number of delivery errors detected
+	Reenabled        uint64 // Reenabled: Number of webhooks re-enabled by the monitor
+	ReenableFailures uint64 // ReenableFailures: Number of failed re-enable attempts
+}
+
+// WebhookMonitor polls GetWebhooks and reports new delivery errors and disabled webhooks.
+// Events are delivered to the OnEvent callback and, if Events was called before Run, to the events channel.
+// The first poll only records the known errors, but reports the webhooks that are already disabled.
+// If Reenable is set, the webhooks the monitor saw being disabled are enabled again with UpdateWebhook
+// once their endpoint passes the health check. Webhooks that were already disabled on the first poll
+// or when they appeared are left alone, as they may have been disabled on purpose.
+type WebhookMonitor struct {
+	Interval    time.Duration                                     // Interval: Polling interval (default: 1 minute)
+	OnEvent     func(ctx context.Context, event WebhookEvent)     // OnEvent: Optional callback called for every event
+	Reenable    bool                                              // Reenable: Re-enable webhooks disabled while monitored after a successful health check
+	HealthCheck func(ctx context.Context, webhook *Webhook) error // HealthCheck: Endpoint health check (default: HTTPHealthCheck with 10 seconds timeout)
+
+	ctd      *Ctd
+	mu       sync.RWMutex
+	webhooks map[int]Webhook
+	tripped  map[int]bool // IDs of the webhooks seen going from enabled to disabled
+	metrics  WebhookMetrics
+	events   chan WebhookEvent
+}
+
+// NewWebhookMonitor creates a new WebhookMonitor with the specified polling interval.
+//
+// Parameters:
+//   - interval: The polling interval. If zero, 1 minute is used.
+//
+// Returns:
+//   - A pointer to a WebhookMonitor.
+func (dst *Ctd) NewWebhookMonitor(interval time.Duration) *WebhookMonitor {
+	return &WebhookMonitor{
+		Interval: interval,
+		ctd:      dst,
+	}
+}
+
+// HTTPHealthCheck returns a health check that sends a GET request to the webhook URL.
+// The endpoint is healthy if it responds with any status below 500.
+//
+// Parameters:
+//   - timeout: The request timeout.
+//
+// Returns:
+//   - The health check function.
+func HTTPHealthCheck(timeout time.Duration) func(ctx context.Context, webhook *Webhook) error {
+	client := &http.Client{Timeout: timeout}
+
+	return func(ctx context.Context, webhook *Webhook) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", webhook.URL, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 500 {
+			return fmt.Errorf("webhook endpoint responded with status %d", resp.StatusCode)
+		}
+
+		return nil
+	}
+}
+
+// Events returns the channel the events are delivered to.
+// The channel is closed when Run returns. If the channel is requested,
+// it must be read, otherwise polling blocks until the context is canceled.
+func (dst *WebhookMonitor) Events() <-chan WebhookEvent {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	if dst.events == nil {
+		dst.events = make(chan WebhookEvent, 100)
+	}
+	return dst.events
+}
+
+// Webhooks returns the webhooks known after the last poll sorted by ID.
+func (dst *WebhookMonitor) Webhooks() []Webhook {
+	dst.mu.RLock()
+	defer dst.mu.RUnlock()
+
+	webhooks := make([]Webhook, 0, len(dst.webhooks))
+	for _, webhook := range dst.webhooks {
+		webhooks = append(webhooks, webhook)
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].ID < webhooks[j].ID
+	})
+
+	return webhooks
+}
+
+// Metrics returns a snapshot of the monitor counters.
+func (dst *WebhookMonitor) Metrics() WebhookMetrics {
+	dst.mu.RLock()
+	defer dst.mu.RUnlock()
+
+	return dst.metrics
+}
+
+// Poll fetches the webhooks once, updates the known state, re-enables the disabled webhooks
+// (if configured) and delivers the detected events.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//
+// Returns:
+//   - A slice of WebhookEvent detected by this poll.
+//   - An error if the request fails or if the response is invalid.
+func (dst *WebhookMonitor) Poll(ctx context.Context) ([]WebhookEvent, error) {
+	webhooks, err := dst.ctd.GetWebhooks(ctx)
+	if err != nil {
+		dst.mu.Lock()
+		dst.metrics.PollErrors++
+		dst.mu.Unlock()
+		return nil, err
+	}
+
+	current := make(map[int]Webhook, len(webhooks))
+	disabled := 0
+	for _, webhook := range webhooks {
+		current[webhook.ID] = webhook
+		if isWebhookDisabled(&webhook) {
+			disabled++
+		}
+	}
+
+	dst.mu.Lock()
+	previous := dst.webhooks
+	dst.webhooks = current
+	dst.trackDisabled(previous, current)
+	tripped := []Webhook{}
+	for _, webhook := range webhooks {
+		if dst.tripped[webhook.ID] {
+			tripped = append(tripped, webhook)
+		}
+	}
+	events := dst.events
+	dst.metrics.Polls++
+	dst.metrics.Webhooks = len(current)
+	dst.metrics.Disabled = disabled
+	dst.mu.Unlock()
+
+	result := webhookEvents(previous, current, time.Now())
+	if dst.Reenable {
+		for _, webhook := range tripped {
+			result = append(result, dst.reenable(ctx, webhook))
+		}
+	}
+
+	dst.mu.Lock()
+	for _, event := range result {
+		switch {
+		case event.Type == WebhookEventError:
+			dst.metrics.Errors += uint64(len(event.Errors))
+		case event.Type == WebhookEventReenabled && event.Error == nil:
+			dst.metrics.Reenabled++
+		case event.Type == WebhookEventReenabled:
+			dst.metrics.ReenableFailures++
+		}
+	}
+	dst.mu.Unlock()
+
+	for _, event := range result {
+		if dst.OnEvent != nil {
+			dst.OnEvent(ctx, event)
+		}
+		if events != nil {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return result, ctx.Err()
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Run polls the webhooks with the configured interval until the context is canceled.
+// Polling errors are logged and polling continues.
+//
+// Parameters:
+//   - ctx: The context controlling the monitor lifetime.
+//
+// Returns:
+//   - The context error when the context is canceled.
+func (dst *WebhookMonitor) Run(ctx context.Context) error {
+	defer func() {
+		dst.mu.Lock()
+		if dst.events != nil {
+			close(dst.events)
+			dst.events = nil
+		}
+		dst.mu.Unlock()
+	}()
+
+	interval := dst.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := dst.Poll(ctx); err != nil && ctx.Err() == nil {
+			dst.ctd.Error(ctx, "Failed to poll webhooks: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// reenable checks the endpoint of a disabled webhook and enables the webhook if the endpoint is healthy.
+func (dst *WebhookMonitor) reenable(ctx context.Context, webhook Webhook) WebhookEvent {
+	event := WebhookEvent{Type: WebhookEventReenabled, Webhook: webhook, Time: time.Now()}
+
+	check := dst.HealthCheck
+	if check == nil {
+		check = HTTPHealthCheck(10 * time.Second)
+	}
+	if event.Error = check(ctx, &webhook); event.Error != nil {
+		return event
+	}
+
+	payload := WebhookPayload{
+		Name:     webhook.Name,
+		URL:      webhook.URL,
+		Events:   webhook.Events,
+		Channels: webhook.Channels,
+		Status:   "enable",
+	}
+	if payload.Channels == nil {
+		payload.Channels = []int{}
+	}
+
+	updated, err := dst.ctd.UpdateWebhook(ctx, webhook.ID, &payload)
+	if err != nil {
+		event.Error = err
+		return event
+	}
+	event.Webhook = *updated
+
+	dst.mu.Lock()
+	if _, ok := dst.webhooks[webhook.ID]; ok {
+		dst.webhooks[webhook.ID] = *updated
+	}
+	delete(dst.tripped, webhook.ID)
+	dst.mu.Unlock()
+
+	return event
+}
+
+// trackDisabled records the webhooks that went from enabled to disabled between the snapshots
+// and forgets the ones that are enabled or deleted. It must be called with the lock held.
+func (dst *WebhookMonitor) trackDisabled(previous, current map[int]Webhook) {
+	if dst.tripped == nil {
+		dst.tripped = map[int]bool{}
+	}
+
+	for id, webhook := range current {
+		if before, ok := previous[id]; ok && !isWebhookDisabled(&before) && isWebhookDisabled(&webhook) {
+			dst.tripped[id] = true
+		}
+	}
+	for id := range dst.tripped {
+		if webhook, ok := current[id]; !ok || !isWebhookDisabled(&webhook) {
+			delete(dst.tripped, id)
+		}
+	}
+}
+
+// webhookEvents compares two snapshots of webhooks and returns the health changes sorted by webhook ID.
+// Without the previous snapshot only the disabled webhooks are reported.
+func webhookEvents(previous, current map[int]Webhook, now time.Time) []WebhookEvent {
+	events := []WebhookEvent{}
+
+	for id, webhook := range current {
+		before, ok := previous[id]
+		if previous == nil || !ok {
+			if isWebhookDisabled(&webhook) {
+				events = append(events, WebhookEvent{Type: WebhookEventDisabled, Webhook: webhook, Time: now})
+			}
+			if previous != nil && len(webhook.Errors) > 0 {
+				events = append(events, WebhookEvent{Type: WebhookEventError, Webhook: webhook, Errors: webhook.Errors, Time: now})
+			}
+			continue
+		}
+
+		switch {
+		case !isWebhookDisabled(&before) && isWebhookDisabled(&webhook):
+			events = append(events, WebhookEvent{Type: WebhookEventDisabled, Webhook: webhook, Time: now})
+		case isWebhookDisabled(&before) && !isWebhookDisabled(&webhook):
+			events = append(events, WebhookEvent{Type: WebhookEventEnabled, Webhook: webhook, Time: now})
+		}
+
+		last := uint64(0)
+		for _, item := range before.Errors {
+			last = max(last, item.CreatedAt)
+		}
+		errors := []WebhookErrors{}
+		for _, item := range webhook.Errors {
+			if item.CreatedAt > last {
+				errors = append(errors, item)
+			}
+		}
+		if len(errors) > 0 {
+			events = append(events, WebhookEvent{Type: WebhookEventError, Webhook: webhook, Errors: errors, Time: now})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Webhook.ID < events[j].Webhook.ID
+	})
+
+	return events
+}
+
+// isWebhookDisabled reports whether the webhook is disabled.
+func isWebhookDisabled(webhook *Webhook) bool {
+	status := strings.ToLower(webhook.Status)
+	return status == "disable" || status == "disabled"
+}
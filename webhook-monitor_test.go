@@ -0,0 +1,140 @@
+package ctd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookEvents(t *testing.T) {
+	now := time.Now()
+
+	previous := map[int]Webhook{
+		1: {ID: 1, Status: "enable", Errors: []WebhookErrors{{Text: "timeout", CreatedAt: 100}}},
+		2: {ID: 2, Status: "enable"},
+		3: {ID: 3, Status: "disable"},
+	}
+	current := map[int]Webhook{
+		1: {ID: 1, Status: "enable", Errors: []WebhookErrors{{Text: "timeout", CreatedAt: 100}, {Text: "500", CreatedAt: 200}}},
+		2: {ID: 2, Status: "disable"},
+		3: {ID: 3, Status: "enable"},
+		4: {ID: 4, Status: "disable"},
+	}
+
+	events := webhookEvents(nil, current, now)
+	require.Len(t, events, 2, "the first poll should report only disabled webhooks")
+	require.Equal(t, WebhookEventDisabled, events[0].Type, "event type")
+	require.Equal(t, 2, events[0].Webhook.ID, "webhook ID")
+	require.Equal(t, 4, events[1].Webhook.ID, "webhook ID")
+
+	events = webhookEvents(previous, current, now)
+	require.Len(t, events, 4, "webhookEvents() should report all changes")
+	require.Equal(t, WebhookEventError, events[0].Type, "event type")
+	require.Equal(t, []WebhookErrors{{Text: "500", CreatedAt: 200}}, events[0].Errors, "only new errors should be reported")
+	require.Equal(t, WebhookEventDisabled, events[1].Type, "event type")
+	require.Equal(t, WebhookEventEnabled, events[2].Type, "event type")
+	require.Equal(t, WebhookEventDisabled, events[3].Type, "event type")
+
+	require.Empty(t, webhookEvents(current, current, now), "webhookEvents() should not report unchanged webhooks")
+}
+
+func TestHTTPHealthCheck(t *testing.T) {
+	ctx := context.Background()
+
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	check := HTTPHealthCheck(time.Second)
+
+	require.NoError(t, check(ctx, &Webhook{URL: server.URL}), "healthy endpoint")
+
+	status = http.StatusMethodNotAllowed
+	require.NoError(t, check(ctx, &Webhook{URL: server.URL}), "endpoint accepting only POST is healthy")
+
+	status = http.StatusBadGateway
+	require.Error(t, check(ctx, &Webhook{URL: server.URL}), "failing endpoint")
+}
+
+func TestWebhookMonitor_Reenable(t *testing.T) {
+	ctx := context.Background()
+
+	mu := sync.Mutex{}
+	statuses := map[int]string{1: "enable", 2: "disable"}
+	updated := []int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == http.MethodPut {
+			id := 0
+			fmt.Sscanf(r.URL.Path, "/v1/webhooks/%d", &id)
+			statuses[id] = "enable"
+			updated = append(updated, id)
+			json.NewEncoder(w).Encode(map[string]any{"status": "success", "data": map[string]any{"id": id, "url": "https://example.com", "status": "enable"}})
+			return
+		}
+
+		data := []map[string]any{}
+		for _, id := range []int{1, 2} {
+			data = append(data, map[string]any{"id": id, "url": "https://example.com", "status": statuses[id]})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"status": "success", "data": data})
+	}))
+	defer server.Close()
+
+	dst := &Ctd{}
+	dst.Init(server.URL, "token")
+
+	monitor := dst.NewWebhookMonitor(time.Minute)
+	monitor.Reenable = true
+	monitor.HealthCheck = func(ctx context.Context, webhook *Webhook) error { return nil }
+
+	_, err := monitor.Poll(ctx)
+	require.NoError(t, err, "monitor.Poll() error")
+	require.Empty(t, updated, "webhooks disabled before monitoring should not be re-enabled")
+
+	mu.Lock()
+	statuses[1] = "disable"
+	mu.Unlock()
+
+	events, err := monitor.Poll(ctx)
+	require.NoError(t, err, "monitor.Poll() error")
+	require.Equal(t, []int{1}, updated, "only the webhook disabled while monitored should be re-enabled")
+	require.Equal(t, WebhookEventReenabled, events[len(events)-1].Type, "re-enable event should be reported")
+	require.NoError(t, events[len(events)-1].Error, "re-enable should succeed")
+
+	_, err = monitor.Poll(ctx)
+	require.NoError(t, err, "monitor.Poll() error")
+	require.Equal(t, []int{1}, updated, "webhooks should not be re-enabled again")
+	require.Equal(t, uint64(1), monitor.Metrics().Reenabled, "monitor.Metrics() should count re-enabled webhooks")
+}
+
+func TestCtd_WebhookMonitor(t *testing.T) {
+	ctx := context.Background()
+
+	url, token := getCredentials(t)
+
+	dst := &Ctd{}
+	dst.Init(url, token)
+
+	monitor := dst.NewWebhookMonitor(time.Minute)
+	_, err := monitor.Poll(ctx)
+	require.NoError(t, err, "monitor.Poll() error")
+	require.Equal(t, uint64(1), monitor.Metrics().Polls, "monitor.Metrics() should count polls")
+	require.Equal(t, len(monitor.Webhooks()), monitor.Metrics().Webhooks, "monitor.Metrics() should count webhooks")
+
+	dst.Init(url, "invalid token")
+	_, err = monitor.Poll(ctx)
+	require.ErrorIs(t, err, ErrorInvalidToken, "monitor.Poll() should return an error for invalid token")
+	require.Equal(t, uint64(1), monitor.Metrics().PollErrors, "monitor.Metrics() should count poll errors")
+}
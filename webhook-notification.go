@@ -0,0 +1,71 @@
+package ctd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookNotification represents the body of a webhook request sent by Chat2Desk.
+// Only the common fields are decoded, the full body is available in Raw.
+type WebhookNotification struct {
+	HookType   string          `json:"hook_type"`   // HookType: Webhook event (e.g., inbox, outbox, new_request, close_dialog)
+	MessageID  int64           `json:"message_id"`  // MessageID: ID of the message
+	Type       string          `json:"type"`        // Type: Type of the message (e.g., from_client, to_client, system)
+	Text       string          `json:"text"`        // Text: Text of the message
+	Transport  string          `json:"transport"`   // Transport: Transport of the message
+	ClientID   int64           `json:"client_id"`   // ClientID: ID of the client
+	OperatorID int64           `json:"operator_id"` // OperatorID: ID of the operator
+	ChannelID  int64           `json:"channel_id"`  // ChannelID: ID of the channel
+	DialogID   int64           `json:"dialog_id"`   // DialogID: ID of the dialog
+	RequestID  int64           `json:"request_id"`  // RequestID: ID of the request
+	EventTime  json.RawMessage `json:"event_time"`  // EventTime: Time of the event (unix timestamp or date string)
+	Raw        json.RawMessage `json:"-"`           // Raw: Original body of the request
+}
+
+// ParseWebhookNotification decodes the body of a webhook request.
+//
+// Parameters:
+//   - body: The body of the webhook request.
+//
+// Returns:
+//   - A pointer to a WebhookNotification.
+//   - ErrorInvalidParameters if the body is not a JSON object.
+func ParseWebhookNotification(body []byte) (*WebhookNotification, error) {
+	notification := WebhookNotification{}
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, ErrorInvalidParameters
+	}
+	notification.Raw = append(json.RawMessage{}, body...)
+
+	return &notification, nil
+}
+
+// Key returns the identifier of the event used to detect duplicates and replays.
+// It is built from the hook type and the message ID, or from the hash of the body if there is no message ID.
+func (dst *WebhookNotification) Key() string {
+	if dst.MessageID != 0 {
+		return fmt.Sprintf("%s:%d", dst.HookType, dst.MessageID)
+	}
+
+	sum := sha256.Sum256(dst.Raw)
+	return fmt.Sprintf("%s:%s", dst.HookType, hex.EncodeToString(sum[:]))
+}
+
+// Time returns the time of the event, or zero time if it is not specified.
+func (dst *WebhookNotification) Time() time.Time {
+	str := strings.Trim(string(dst.EventTime), `"`)
+	if str == "" || str == "null" {
+		return time.Time{}
+	}
+
+	if value, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return time.Unix(value, 0)
+	}
+
+	return parseTime(str)
+}
@@ -0,0 +1,243 @@
+package ctd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	WebhookActionCreate   = "create"   // Webhook will be created
+	WebhookActionUpdate   = "update"   // Webhook will be updated
+	WebhookActionDelete   = "delete"   // Webhook will be deleted
+	WebhookActionKeep     = "keep"     // Webhook already matches the desired state
+	WebhookActionConflict = "conflict" // URL is used by a webhook outside the ownership prefix
+)
+
+// EnsureWebhooksOptions controls the webhook reconciliation.
+type EnsureWebhooksOptions struct {
+	Prefix string // Prefix: Ownership prefix of the webhook names; only webhooks with this prefix are updated or deleted (required)
+	DryRun bool   // DryRun: Only build the plan without applying it
+}
+
+// WebhookChange describes a single change of the webhook reconciliation plan.
+type WebhookChange struct {
+	Action  string          // Action: Change action ('create', 'update', 'delete', 'keep', 'conflict')
+	Current *Webhook        // Current: Existing webhook (nil for 'create')
+	Desired *WebhookPayload // Desired: Desired webhook (nil for 'delete')
+	Fields  []string        // Fields: Names of the changed fields ('update' only)
+	Error   error           // Error: Error of applying the change
+}
+
+// WebhookPlan is the list of changes required to make the webhooks match the desired set.
+// Changes are ordered the way they are applied: deletions first (to release the URLs), then updates and creations.
+type WebhookPlan struct {
+	Changes []WebhookChange // Changes: Planned changes
+}
+
+// Changed reports whether the plan contains any change to apply.
+func (dst *WebhookPlan) Changed() bool {
+	for _, change := range dst.Changes {
+		switch change.Action {
+		case WebhookActionCreate, WebhookActionUpdate, WebhookActionDelete:
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the human-readable plan, one change per line.
+func (dst *WebhookPlan) String() string {
+	lines := []string{}
+	for _, change := range dst.Changes {
+		var line string
+		switch change.Action {
+		case WebhookActionCreate:
+			line = fmt.Sprintf("+ create %q (%s)", change.Desired.Name, change.Desired.URL)
+		case WebhookActionUpdate:
+			line = fmt.Sprintf("~ update #%d %q (%s): %s", change.Current.ID, change.Desired.Name, change.Desired.URL, strings.Join(change.Fields, ", "))
+		case WebhookActionDelete:
+			line = fmt.Sprintf("- delete #%d %q (%s)", change.Current.ID, change.Current.Name, change.Current.URL)
+		case WebhookActionKeep:
+			line = fmt.Sprintf("= keep #%d %q (%s)", change.Current.ID, change.Current.Name, change.Current.URL)
+		case WebhookActionConflict:
+			line = fmt.Sprintf("! conflict %q (%s): URL is used by #%d %q", change.Desired.Name, change.Desired.URL, change.Current.ID, change.Current.Name)
+		}
+		if change.Error != nil {
+			line = fmt.Sprintf("%s: %v", line, change.Error)
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// EnsureWebhooks makes the webhooks of the company match the desired set.
+// Existing webhooks are matched to the desired ones by URL first and then by name.
+// If a webhook verifier is configured, its secret is added to the desired URLs before matching.
+// Matched webhooks are updated if they differ, missing ones are created and
+// unmatched webhooks whose name starts with the ownership prefix are deleted.
+// Webhooks outside the prefix are never updated or deleted; if one of them uses a desired URL,
+// the change is reported as a conflict and ErrorWebhookUrlIsAlreadyUsed is returned.
+// Errors of single changes don't stop the reconciliation and are reported in the plan.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - desired: The desired webhooks. Every name must start with the ownership prefix.
+//   - options: The reconciliation options. The ownership prefix is required.
+//
+// Returns:
+//   - A pointer to a WebhookPlan describing the planned (dry run) or applied changes.
+//   - ErrorInvalidParameters if the options or the desired set are invalid,
+//     the first error of the applied changes, or an error if the request fails.
+func (dst *Ctd) EnsureWebhooks(ctx context.Context, desired []WebhookPayload, options *EnsureWebhooksOptions) (*WebhookPlan, error) {
+	if options == nil || options.Prefix == "" {
+		dst.Error(ctx, "Webhook ownership prefix is not set")
+		return nil, ErrorInvalidParameters
+	}
+
+	current, err := dst.GetWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := make([]WebhookPayload, 0, len(desired))
+	for i := range desired {
+		payload, err := dst.signWebhookPayload(ctx, &desired[i])
+		if err != nil {
+			return nil, err
+		}
+		signed = append(signed, *payload)
+	}
+
+	plan, err := planWebhooks(current, signed, options.Prefix)
+	if err != nil {
+		dst.Error(ctx, "Invalid desired webhooks: %v", err)
+		return nil, ErrorInvalidParameters
+	}
+
+	if options.DryRun {
+		return plan, nil
+	}
+
+	var result error
+	for i := range plan.Changes {
+		change := &plan.Changes[i]
+		switch change.Action {
+		case WebhookActionCreate:
+			_, change.Error = dst.CreateWebhook(ctx, change.Desired)
+		case WebhookActionUpdate:
+			_, change.Error = dst.UpdateWebhook(ctx, change.Current.ID, change.Desired)
+		case WebhookActionDelete:
+			change.Error = dst.DeleteWebhook(ctx, change.Current.ID)
+		case WebhookActionConflict:
+			change.Error = ErrorWebhookUrlIsAlreadyUsed
+		}
+		if change.Error != nil && result == nil {
+			result = change.Error
+		}
+	}
+
+	return plan, result
+}
+
+// planWebhooks builds the reconciliation plan for the current and desired webhooks.
+func planWebhooks(current []Webhook, desired []WebhookPayload, prefix string) (*WebhookPlan, error) {
+	urls := map[string]bool{}
+	names := map[string]bool{}
+	for _, payload := range desired {
+		if !strings.HasPrefix(payload.Name, prefix) {
+			return nil, fmt.Errorf("webhook %q is outside the ownership prefix %q", payload.Name, prefix)
+		}
+		if payload.URL == "" {
+			return nil, fmt.Errorf("webhook %q has no URL", payload.Name)
+		}
+		if urls[payload.URL] || names[payload.Name] {
+			return nil, fmt.Errorf("webhook %q (%s) is duplicated", payload.Name, payload.URL)
+		}
+		urls[payload.URL] = true
+		names[payload.Name] = true
+	}
+
+	matched := make([]bool, len(current))
+	find := func(match func(webhook *Webhook) bool) int {
+		for i := range current {
+			if !matched[i] && match(&current[i]) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	deletes := []WebhookChange{}
+	changes := []WebhookChange{}
+	for _, payload := range desired {
+		payload.Prepare()
+		if payload.Channels == nil {
+			payload.Channels = []int{}
+		}
+
+		index := find(func(webhook *Webhook) bool { return webhook.URL == payload.URL })
+		if index >= 0 && !strings.HasPrefix(current[index].Name, prefix) {
+			matched[index] = true
+			changes = append(changes, WebhookChange{Action: WebhookActionConflict, Current: &current[index], Desired: &payload})
+			continue
+		}
+		if index < 0 {
+			index = find(func(webhook *Webhook) bool {
+				return webhook.Name == payload.Name && strings.HasPrefix(webhook.Name, prefix)
+			})
+		}
+
+		if index < 0 {
+			changes = append(changes, WebhookChange{Action: WebhookActionCreate, Desired: &payload})
+			continue
+		}
+
+		matched[index] = true
+		fields := webhookDiff(&current[index], &payload)
+		if len(fields) == 0 {
+			changes = append(changes, WebhookChange{Action: WebhookActionKeep, Current: &current[index], Desired: &payload})
+			continue
+		}
+		changes = append(changes, WebhookChange{Action: WebhookActionUpdate, Current: &current[index], Desired: &payload, Fields: fields})
+	}
+
+	for i := range current {
+		if !matched[i] && strings.HasPrefix(current[i].Name, prefix) {
+			deletes = append(deletes, WebhookChange{Action: WebhookActionDelete, Current: &current[i]})
+		}
+	}
+
+	return &WebhookPlan{Changes: append(deletes, changes...)}, nil
+}
+
+// webhookDiff returns the names of the fields that differ between the webhook and the payload.
+// Events and channels are compared as sets.
+func webhookDiff(webhook *Webhook, payload *WebhookPayload) []string {
+	fields := []string{}
+	if webhook.Name != payload.Name {
+		fields = append(fields, "name")
+	}
+	if webhook.URL != payload.URL {
+		fields = append(fields, "url")
+	}
+	if !sameSet(webhook.Events, payload.Events) {
+		fields = append(fields, "events")
+	}
+	if !sameSet(webhook.Channels, payload.Channels) {
+		fields = append(fields, "channels")
+	}
+	if !strings.EqualFold(webhook.Status, payload.Status) {
+		fields = append(fields, "status")
+	}
+	return fields
+}
+
+// sameSet reports whether two slices contain the same unique values regardless of order.
+func sameSet[T int | string](a, b []T) bool {
+	a = slices.Compact(slices.Sorted(slices.Values(a)))
+	b = slices.Compact(slices.Sorted(slices.Values(b)))
+	return slices.Equal(a, b)
+}
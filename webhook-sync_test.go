@@ -0,0 +1,91 @@
+package ctd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanWebhooks(t *testing.T) {
+	current := []Webhook{
+		{ID: 1, Name: "app: inbox", URL: "https://app/inbox", Events: []string{"inbox"}, Channels: []int{}, Status: "enable"},
+		{ID: 2, Name: "app: outbox", URL: "https://app/outbox", Events: []string{"outbox"}, Channels: []int{}, Status: "enable"},
+		{ID: 3, Name: "app: stale", URL: "https://app/stale", Events: []string{"inbox"}, Channels: []int{}, Status: "enable"},
+		{ID: 4, Name: "crm", URL: "https://crm/hook", Events: []string{"inbox"}, Channels: []int{}, Status: "enable"},
+		{ID: 5, Name: "other", URL: "https://other/hook", Events: []string{"inbox"}, Channels: []int{}, Status: "enable"},
+	}
+	desired := []WebhookPayload{
+		{Name: "app: inbox", URL: "https://app/inbox", Events: []string{"inbox"}},
+		{Name: "app: outbox", URL: "https://app/outbox/v2", Events: []string{"outbox", "outbox_status"}, Status: "disable"},
+		{Name: "app: new", URL: "https://app/new", Events: []string{"new_request"}},
+		{Name: "app: crm", URL: "https://crm/hook", Events: []string{"inbox"}},
+	}
+
+	plan, err := planWebhooks(current, desired, "app: ")
+	require.NoError(t, err, "planWebhooks() error")
+	require.True(t, plan.Changed(), "plan.Changed() should report changes")
+
+	actions := map[string][]string{}
+	for _, change := range plan.Changes {
+		name := ""
+		if change.Desired != nil {
+			name = change.Desired.Name
+		} else {
+			name = change.Current.Name
+		}
+		actions[change.Action] = append(actions[change.Action], name)
+	}
+	require.Equal(t, []string{"app: stale"}, actions[WebhookActionDelete], "only owned unmatched webhooks should be deleted")
+	require.Equal(t, []string{"app: inbox"}, actions[WebhookActionKeep], "unchanged webhooks should be kept")
+	require.Equal(t, []string{"app: outbox"}, actions[WebhookActionUpdate], "changed webhooks should be updated")
+	require.Equal(t, []string{"app: new"}, actions[WebhookActionCreate], "missing webhooks should be created")
+	require.Equal(t, []string{"app: crm"}, actions[WebhookActionConflict], "webhooks outside the prefix should not be touched")
+	require.Equal(t, WebhookActionDelete, plan.Changes[0].Action, "deletions should go first")
+
+	for _, change := range plan.Changes {
+		if change.Action == WebhookActionUpdate {
+			require.Equal(t, []string{"url", "events", "status"}, change.Fields, "changed fields")
+		}
+	}
+
+	_, err = planWebhooks(current, []WebhookPayload{{Name: "foreign", URL: "https://app/x"}}, "app: ")
+	require.Error(t, err, "planWebhooks() should reject webhooks outside the prefix")
+
+	_, err = planWebhooks(current, []WebhookPayload{{Name: "app: a", URL: "https://app/x"}, {Name: "app: b", URL: "https://app/x"}}, "app: ")
+	require.Error(t, err, "planWebhooks() should reject duplicated URLs")
+
+	plan, err = planWebhooks(current[:1], desired[:1], "app: ")
+	require.NoError(t, err, "planWebhooks() error")
+	require.False(t, plan.Changed(), "plan.Changed() should not report changes for matching webhooks")
+}
+
+func TestCtd_EnsureWebhooks(t *testing.T) {
+	ctx := context.Background()
+
+	url, token := getCredentials(t)
+
+	dst := &Ctd{}
+	dst.Init(url, token)
+
+	desired := []WebhookPayload{
+		{Name: "ctd-test: inbox", URL: "https://localhost/ctd-test/inbox", Events: []string{"inbox"}, Status: "enable"},
+	}
+
+	_, err := dst.EnsureWebhooks(ctx, desired, nil)
+	require.ErrorIs(t, err, ErrorInvalidParameters, "dst.EnsureWebhooks() should require the ownership prefix")
+
+	plan, err := dst.EnsureWebhooks(ctx, desired, &EnsureWebhooksOptions{Prefix: "ctd-test: ", DryRun: true})
+	require.NoError(t, err, "dst.EnsureWebhooks() dry run error")
+	require.NotEmpty(t, plan.Changes, "dst.EnsureWebhooks() dry run should return the plan")
+
+	plan, err = dst.EnsureWebhooks(ctx, desired, &EnsureWebhooksOptions{Prefix: "ctd-test: "})
+	require.NoError(t, err, "dst.EnsureWebhooks() error")
+
+	plan, err = dst.EnsureWebhooks(ctx, desired, &EnsureWebhooksOptions{Prefix: "ctd-test: ", DryRun: true})
+	require.NoError(t, err, "dst.EnsureWebhooks() dry run error")
+	require.False(t, plan.Changed(), "dst.EnsureWebhooks() should be idempotent: %s", plan)
+
+	_, err = dst.EnsureWebhooks(ctx, []WebhookPayload{}, &EnsureWebhooksOptions{Prefix: "ctd-test: "})
+	require.NoError(t, err, "dst.EnsureWebhooks() cleanup error")
+}
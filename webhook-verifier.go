@@ -0,0 +1,328 @@
+package ctd
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ra-company/logging"
+)
+
+var (
+	ErrorWebhookUnauthorized = fmt.Errorf("webhook request is not authorized")
+	ErrorWebhookReplayed     = fmt.Errorf("webhook event is replayed")
+)
+
+// SeenStore remembers the keys of the processed webhook events.
+// Implementations must be safe for concurrent use.
+type SeenStore interface {
+	// Seen marks the key as seen for the specified time and reports whether it was already seen.
+	Seen(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Forget removes the key, so the event can be processed again.
+	Forget(ctx context.Context, key string) error
+}
+
+// MemorySeenStore is an in-memory SeenStore. Expired keys are removed lazily.
+type MemorySeenStore struct {
+	mu      sync.Mutex
+	keys    map[string]time.Time
+	cleaned time.Time
+}
+
+// NewMemorySeenStore creates a new empty MemorySeenStore.
+//
+// Returns:
+//   - A pointer to a MemorySeenStore.
+func NewMemorySeenStore() *MemorySeenStore {
+	return &MemorySeenStore{
+		keys: map[string]time.Time{},
+	}
+}
+
+// Seen marks the key as seen for the specified time and reports whether it was already seen.
+func (dst *MemorySeenStore) Seen(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(dst.cleaned) > time.Minute {
+		for item, expires := range dst.keys {
+			if now.After(expires) {
+				delete(dst.keys, item)
+			}
+		}
+		dst.cleaned = now
+	}
+
+	if expires, ok := dst.keys[key]; ok && now.Before(expires) {
+		return true, nil
+	}
+	dst.keys[key] = now.Add(ttl)
+
+	return false, nil
+}
+
+// Forget removes the key, so the event can be processed again.
+func (dst *MemorySeenStore) Forget(ctx context.Context, key string) error {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	delete(dst.keys, key)
+
+	return nil
+}
+
+// WebhookVerifier verifies the webhook requests sent by Chat2Desk.
+// All the configured checks must pass:
+//   - the shared secret must be present in the query parameter of the URL;
+//   - the sender IP must be in the allowlist;
+//   - the HMAC-SHA256 signature of the body must be present in the header;
+//   - the event must not be older than MaxAge and must not be seen before.
+//
+// Checks with empty settings are skipped.
+type WebhookVerifier struct {
+	Secret      string        // Secret: Shared secret added to the webhook URL
+	SecretParam string        // SecretParam: Name of the query parameter with the secret (default: secret)
+	AllowedIPs  []string      // AllowedIPs: Allowed sender IP addresses or CIDR ranges
+	TrustProxy  bool          // TrustProxy: Take the sender IP from the X-Forwarded-For and X-Real-IP headers
+	Proxies     []string      // Proxies: IP addresses or CIDR ranges of the proxy hops skipped in X-Forwarded-For, besides the nearest proxy
+	HMACKey     string        // HMACKey: Key of the HMAC-SHA256 body signature
+	HMACHeader  string        // HMACHeader: Header with the hex-encoded signature (default: X-Signature)
+	MaxAge      time.Duration // MaxAge: Maximum age of the event, checked if the event time is present
+	ReplayTTL   time.Duration // ReplayTTL: How long the event keys are remembered (default: 24 hours)
+	Store       SeenStore     // Store: Store of the seen event keys (nil - no replay protection)
+}
+
+// NewWebhookVerifier creates a new WebhookVerifier with the shared secret
+// and an in-memory replay protection store.
+//
+// Parameters:
+//   - secret: The shared secret added to the webhook URL.
+//
+// Returns:
+//   - A pointer to a WebhookVerifier.
+func NewWebhookVerifier(secret string) *WebhookVerifier {
+	return &WebhookVerifier{
+		Secret: secret,
+		Store:  NewMemorySeenStore(),
+	}
+}
+
+// SignURL adds the shared secret to the webhook URL, replacing the existing one.
+//
+// Parameters:
+//   - str: The webhook URL.
+//
+// Returns:
+//   - The URL with the secret.
+//   - ErrorInvalidParameters if the URL can't be parsed.
+func (dst *WebhookVerifier) SignURL(str string) (string, error) {
+	if dst == nil || dst.Secret == "" {
+		return str, nil
+	}
+
+	u, err := url.Parse(str)
+	if err != nil {
+		return "", ErrorInvalidParameters
+	}
+
+	query := u.Query()
+	query.Set(dst.secretParam(), dst.Secret)
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// Verify checks the webhook request and decodes its body.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - r: The webhook request.
+//   - body: The body of the request.
+//
+// Returns:
+//   - A pointer to a WebhookNotification.
+//   - ErrorWebhookUnauthorized if the request fails a check, ErrorWebhookReplayed if the event was already seen
+//     or is too old, ErrorInvalidParameters if the body is invalid, or the store error.
+func (dst *WebhookVerifier) Verify(ctx context.Context, r *http.Request, body []byte) (*WebhookNotification, error) {
+	if dst.Secret != "" {
+		secret := r.URL.Query().Get(dst.secretParam())
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(dst.Secret)) != 1 {
+			return nil, ErrorWebhookUnauthorized
+		}
+	}
+
+	if len(dst.AllowedIPs) > 0 && !dst.allowedIP(dst.clientIP(r)) {
+		return nil, ErrorWebhookUnauthorized
+	}
+
+	if dst.HMACKey != "" {
+		header := dst.HMACHeader
+		if header == "" {
+			header = "X-Signature"
+		}
+		signature, err := hex.DecodeString(strings.TrimPrefix(r.Header.Get(header), "sha256="))
+		if err != nil {
+			return nil, ErrorWebhookUnauthorized
+		}
+		mac := hmac.New(sha256.New, []byte(dst.HMACKey))
+		mac.Write(body)
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return nil, ErrorWebhookUnauthorized
+		}
+	}
+
+	notification, err := ParseWebhookNotification(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if at := notification.Time(); dst.MaxAge > 0 && !at.IsZero() && time.Since(at) > dst.MaxAge {
+		return nil, ErrorWebhookReplayed
+	}
+
+	if dst.Store != nil {
+		ttl := dst.ReplayTTL
+		if ttl <= 0 {
+			ttl = 24 * time.Hour
+		}
+		seen, err := dst.Store.Seen(ctx, notification.Key(), ttl)
+		if err != nil {
+			return nil, err
+		}
+		if seen {
+			return nil, ErrorWebhookReplayed
+		}
+	}
+
+	return notification, nil
+}
+
+// Handler returns an HTTP handler verifying the webhook requests and passing them to the callback.
+// Unauthorized requests get 403, invalid bodies get 400, callback errors get 500
+// and full or closed dispatcher errors get 503, so Chat2Desk repeats them;
+// the key of a failed event is forgotten, so the repeated request isn't rejected as replayed.
+// Replayed events get 200, so they are not repeated.
+//
+// Parameters:
+//   - next: The callback processing the verified notifications.
+//
+// Returns:
+//   - The HTTP handler.
+func (dst *WebhookVerifier) Handler(next func(ctx context.Context, notification *WebhookNotification) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 10<<20))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		notification, err := dst.Verify(r.Context(), r, body)
+		switch err {
+		case nil:
+		case ErrorWebhookReplayed:
+			w.WriteHeader(http.StatusOK)
+			return
+		case ErrorWebhookUnauthorized:
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		case ErrorInvalidParameters:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		err = next(r.Context(), notification)
+		if err != nil && err != ErrorWebhookReplayed && dst.Store != nil {
+			// Let the repeated request through
+			if err := dst.Store.Forget(r.Context(), notification.Key()); err != nil {
+				logging.Logs.Errorf(r.Context(), "Failed to forget webhook event %s: %v", notification.Key(), err)
+			}
+		}
+
+		switch err {
+		case nil, ErrorWebhookReplayed:
+		case ErrorDispatcherFull, ErrorDispatcherClosed:
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// secretParam returns the name of the query parameter with the secret.
+func (dst *WebhookVerifier) secretParam() string {
+	if dst.SecretParam == "" {
+		return "secret"
+	}
+	return dst.SecretParam
+}
+
+// clientIP returns the IP address of the sender.
+func (dst *WebhookVerifier) clientIP(r *http.Request) string {
+	if dst.TrustProxy {
+		// The leftmost entries are set by the client, so the list is walked from the right past the known proxies
+		forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+		for i := len(forwarded) - 1; i >= 0; i-- {
+			item := strings.TrimSpace(forwarded[i])
+			if item == "" {
+				continue
+			}
+			if i == 0 || !ipInList(item, dst.Proxies) {
+				return item
+			}
+		}
+		if real := r.Header.Get("X-Real-IP"); real != "" {
+			return strings.TrimSpace(real)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// allowedIP reports whether the IP address is in the allowlist.
+func (dst *WebhookVerifier) allowedIP(str string) bool {
+	return ipInList(str, dst.AllowedIPs)
+}
+
+// ipInList reports whether the IP address matches one of the addresses or CIDR ranges.
+func ipInList(str string, list []string) bool {
+	ip := net.ParseIP(str)
+	if ip == nil {
+		return false
+	}
+
+	for _, item := range list {
+		if strings.Contains(item, "/") {
+			if _, network, err := net.ParseCIDR(item); err == nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if allowed := net.ParseIP(item); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
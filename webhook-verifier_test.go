@@ -0,0 +1,148 @@
+package ctd
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookVerifier_SignURL(t *testing.T) {
+	verifier := NewWebhookVerifier("s3cr3t")
+
+	got, err := verifier.SignURL("https://example.com/hook?a=1")
+	require.NoError(t, err, "verifier.SignURL() error")
+	require.Equal(t, "https://example.com/hook?a=1&secret=s3cr3t", got, "verifier.SignURL() should add the secret")
+
+	got, err = verifier.SignURL(got)
+	require.NoError(t, err, "verifier.SignURL() error")
+	require.Equal(t, "https://example.com/hook?a=1&secret=s3cr3t", got, "verifier.SignURL() should replace the secret")
+
+	var empty *WebhookVerifier
+	got, err = empty.SignURL("https://example.com/hook")
+	require.NoError(t, err, "nil verifier should not sign URLs")
+	require.Equal(t, "https://example.com/hook", got, "nil verifier should not sign URLs")
+}
+
+func TestWebhookVerifier_Verify(t *testing.T) {
+	ctx := context.Background()
+
+	body := `{"hook_type":"inbox","message_id":42,"text":"hello"}`
+	request := func(target, remote string, headers map[string]string) *http.Request {
+		r := httptest.NewRequest("POST", target, strings.NewReader(body))
+		r.RemoteAddr = remote
+		for key, value := range headers {
+			r.Header.Set(key, value)
+		}
+		return r
+	}
+
+	t.Run("Secret", func(t *testing.T) {
+		verifier := NewWebhookVerifier("s3cr3t")
+
+		_, err := verifier.Verify(ctx, request("/hook?secret=wrong", "1.2.3.4:1000", nil), []byte(body))
+		require.ErrorIs(t, err, ErrorWebhookUnauthorized, "wrong secret should be rejected")
+
+		got, err := verifier.Verify(ctx, request("/hook?secret=s3cr3t", "1.2.3.4:1000", nil), []byte(body))
+		require.NoError(t, err, "valid secret should be accepted")
+		require.Equal(t, int64(42), got.MessageID, "notification should be decoded")
+		require.Equal(t, "inbox:42", got.Key(), "notification key")
+
+		_, err = verifier.Verify(ctx, request("/hook?secret=s3cr3t", "1.2.3.4:1000", nil), []byte(body))
+		require.ErrorIs(t, err, ErrorWebhookReplayed, "repeated event should be rejected")
+	})
+
+	t.Run("IP allowlist", func(t *testing.T) {
+		verifier := &WebhookVerifier{AllowedIPs: []string{"10.0.0.0/8", "1.2.3.4"}}
+
+		_, err := verifier.Verify(ctx, request("/hook", "1.2.3.4:1000", nil), []byte(body))
+		require.NoError(t, err, "allowed IP should be accepted")
+		_, err = verifier.Verify(ctx, request("/hook", "10.1.2.3:1000", nil), []byte(body))
+		require.NoError(t, err, "allowed network should be accepted")
+		_, err = verifier.Verify(ctx, request("/hook", "5.6.7.8:1000", map[string]string{"X-Forwarded-For": "1.2.3.4"}), []byte(body))
+		require.ErrorIs(t, err, ErrorWebhookUnauthorized, "forwarded IP should be ignored without TrustProxy")
+
+		verifier.TrustProxy = true
+		_, err = verifier.Verify(ctx, request("/hook", "5.6.7.8:1000", map[string]string{"X-Forwarded-For": "1.2.3.4"}), []byte(body))
+		require.NoError(t, err, "forwarded IP should be used with TrustProxy")
+		_, err = verifier.Verify(ctx, request("/hook", "5.6.7.8:1000", map[string]string{"X-Forwarded-For": "1.2.3.4, 9.9.9.9"}), []byte(body))
+		require.ErrorIs(t, err, ErrorWebhookUnauthorized, "spoofed leftmost entry should be ignored")
+
+		verifier.Proxies = []string{"192.168.0.0/16"}
+		_, err = verifier.Verify(ctx, request("/hook", "5.6.7.8:1000", map[string]string{"X-Forwarded-For": "1.2.3.4, 192.168.1.1"}), []byte(body))
+		require.NoError(t, err, "known proxy hops should be skipped")
+		_, err = verifier.Verify(ctx, request("/hook", "5.6.7.8:1000", map[string]string{"X-Forwarded-For": "1.2.3.4, 9.9.9.9, 192.168.1.1"}), []byte(body))
+		require.ErrorIs(t, err, ErrorWebhookUnauthorized, "entries left of an unknown hop should be ignored")
+	})
+
+	t.Run("HMAC", func(t *testing.T) {
+		verifier := &WebhookVerifier{HMACKey: "key"}
+
+		mac := hmac.New(sha256.New, []byte("key"))
+		mac.Write([]byte(body))
+		signature := hex.EncodeToString(mac.Sum(nil))
+
+		_, err := verifier.Verify(ctx, request("/hook", "1.2.3.4:1000", map[string]string{"X-Signature": signature}), []byte(body))
+		require.NoError(t, err, "valid signature should be accepted")
+		_, err = verifier.Verify(ctx, request("/hook", "1.2.3.4:1000", map[string]string{"X-Signature": "00" + signature[2:]}), []byte(body))
+		require.ErrorIs(t, err, ErrorWebhookUnauthorized, "invalid signature should be rejected")
+	})
+
+	t.Run("Max age", func(t *testing.T) {
+		verifier := &WebhookVerifier{MaxAge: time.Minute}
+
+		old := `{"hook_type":"inbox","message_id":1,"event_time":1000}`
+		_, err := verifier.Verify(ctx, request("/hook", "1.2.3.4:1000", nil), []byte(old))
+		require.ErrorIs(t, err, ErrorWebhookReplayed, "old event should be rejected")
+	})
+}
+
+func TestWebhookVerifier_Handler(t *testing.T) {
+	received := 0
+	handler := NewWebhookVerifier("s3cr3t").Handler(func(ctx context.Context, notification *WebhookNotification) error {
+		received++
+		return nil
+	})
+
+	send := func(target string) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", target, strings.NewReader(`{"hook_type":"inbox","message_id":7}`)))
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusForbidden, send("/hook"), "request without secret")
+	require.Equal(t, http.StatusOK, send("/hook?secret=s3cr3t"), "valid request")
+	require.Equal(t, http.StatusOK, send("/hook?secret=s3cr3t"), "replayed request")
+	require.Equal(t, 1, received, "replayed request should not be processed")
+}
+
+func TestWebhookVerifier_HandlerRetry(t *testing.T) {
+	calls := 0
+	verifier := NewWebhookVerifier("s3cr3t")
+	handler := verifier.Handler(func(ctx context.Context, notification *WebhookNotification) error {
+		calls++
+		if calls == 1 {
+			return ErrorUnknownError
+		}
+		return nil
+	})
+
+	send := func() int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", "/hook?secret=s3cr3t", strings.NewReader(`{"hook_type":"inbox","message_id":8}`)))
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusInternalServerError, send(), "failed callback")
+	require.Equal(t, http.StatusOK, send(), "repeated request after the failure")
+	require.Equal(t, 2, calls, "repeated request should be processed")
+	require.Equal(t, http.StatusOK, send(), "replayed request")
+	require.Equal(t, 2, calls, "replayed request after the success should not be processed")
+}
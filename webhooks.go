@@ -275,6 +275,7 @@ func (dst *Ctd) GetWebhooks(ctx context.Context) ([]Webhook, error) {
 // It calls the PostWebhook method to send the request.
 // If the response status is not "success", it logs an error and returns nil.
 // If the URL is already used, it returns an error indicating that the URL is already used.
+// If a webhook verifier is configured, its secret is added to the URL.
 // If the request is successful, it returns a pointer to the created Webhook.
 // This method is typically used to create new webhooks in the Chat2Desk API.
 //
@@ -286,6 +287,11 @@ func (dst *Ctd) GetWebhooks(ctx context.Context) ([]Webhook, error) {
 //   - A pointer to a Webhook containing the created webhook.
 //   - An error if the request fails or if the response is invalid.
 func (dst *Ctd) CreateWebhook(ctx context.Context, payload *WebhookPayload) (*Webhook, error) {
+	payload, err := dst.signWebhookPayload(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
 	response, err := dst.PostWebhooks(ctx, payload)
 	if err != nil {
 		return nil, err
@@ -304,6 +310,7 @@ func (dst *Ctd) CreateWebhook(ctx context.Context, payload *WebhookPayload) (*We
 // It calls the PutWebhooks method to send the request.
 // If the response status is not "success", it logs an error and returns nil.
 // If the URL is already used, it returns an error indicating that the URL is already used.
+// If a webhook verifier is configured, its secret is added to the URL.
 // If the request is successful, it returns a pointer to the updated Webhook.
 // This method is typically used to update existing webhooks in the Chat2Desk API.
 //
@@ -316,6 +323,11 @@ func (dst *Ctd) CreateWebhook(ctx context.Context, payload *WebhookPayload) (*We
 //   - A pointer to a Webhook containing the updated webhook.
 //   - An error if the request fails or if the response is invalid.
 func (dst *Ctd) UpdateWebhook(ctx context.Context, id int, payload *WebhookPayload) (*Webhook, error) {
+	payload, err := dst.signWebhookPayload(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
 	response, err := dst.PutWebhooks(ctx, id, payload)
 	if err != nil {
 		return nil, err
@@ -355,3 +367,21 @@ func (dst *Ctd) DeleteWebhook(ctx context.Context, id int) error {
 
 	return nil
 }
+
+// signWebhookPayload returns a copy of the payload with the secret of the webhook verifier added to the URL.
+// If no verifier is configured, the payload is returned as is.
+func (dst *Ctd) signWebhookPayload(ctx context.Context, payload *WebhookPayload) (*WebhookPayload, error) {
+	if dst.Verifier == nil || dst.Verifier.Secret == "" {
+		return payload, nil
+	}
+
+	signed := *payload
+	url, err := dst.Verifier.SignURL(payload.URL)
+	if err != nil {
+		dst.Error(ctx, "Invalid webhook URL: %s", dst.redact(payload.URL))
+		return nil, err
+	}
+	signed.URL = url
+
+	return &signed, nil
+}